@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// Exit codes.
+const (
+	exitOK           = 0
+	exitVerifyFailed = 1
+	exitError        = 2
+)
+
+const (
+	defaultSmockerURL   = "http://localhost:8081"
+	smockerURLEnv       = "SMOCKER_URL"
+	defaultReadyTimeout = 30 * time.Second
+)
+
+const usage = `Usage: infestor [-url URL] <command> [arguments]
+
+Commands:
+  reset [-keep-locked]             clear mocks and history, -keep-locked keeps locked mocks
+  deploy [-timeout D] <scenario>   deploy mocks described in a YAML/JSON scenario file, waiting up to D
+                                   for Smocker to be ready
+  origins                          list registered origins
+  history [-session ID] [-path P]  print the history of calls as JSON
+  verify [-session ID]             verify the session, fails if mocks were unused or requests unmatched
+
+Exit codes:
+  0  success
+  1  verification failed
+  2  error
+
+Smocker admin URL defaults to $SMOCKER_URL or ` + defaultSmockerURL + `.
+`
+
+var errVerifyFailed = errors.New("verification failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("infestor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+	url := fs.String("url", smockerURL(), "Smocker admin API URL")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError
+	}
+
+	api := smocker.NewAPI(*url)
+	ctx := context.Background()
+	cmdArgs := fs.Args()[1:]
+
+	var err error
+	switch fs.Arg(0) {
+	case "reset":
+		err = reset(ctx, api, cmdArgs, stderr)
+	case "deploy":
+		err = deploy(ctx, api, cmdArgs, stderr)
+	case "origins":
+		for _, name := range origin.Names() {
+			fmt.Fprintln(stdout, name)
+		}
+	case "history":
+		err = history(ctx, api, cmdArgs, stdout, stderr)
+	case "verify":
+		err = verify(ctx, api, cmdArgs, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command: %s\n", fs.Arg(0))
+		fs.Usage()
+		return exitError
+	}
+
+	switch {
+	case errors.Is(err, errVerifyFailed):
+		return exitVerifyFailed
+	case err != nil:
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func smockerURL() string {
+	if url, ok := os.LookupEnv(smockerURLEnv); ok {
+		return url
+	}
+	return defaultSmockerURL
+}
+
+func reset(ctx context.Context, api *smocker.API, args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keepLocked := fs.Bool("keep-locked", false, "keep locked mocks, like keepLocked in scenarios")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keepLocked {
+		return api.ResetUnlocked(ctx)
+	}
+	return api.Reset(ctx)
+}
+
+func deploy(ctx context.Context, api *smocker.API, args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("deploy", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timeout := fs.Duration("timeout", defaultReadyTimeout, "how long to wait for Smocker to be ready")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("deploy expects exactly one scenario file")
+	}
+	scenario, err := infestor.LoadScenario(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	mb, err := scenario.MocksBuilder()
+	if err != nil {
+		return err
+	}
+	readyCtx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	if err := api.WaitReady(readyCtx); err != nil {
+		if !errors.Is(err, smocker.ErrUnknownVersion) {
+			return err
+		}
+		fmt.Fprintf(stderr, "warning: %v, assuming at least %s\n", err, smocker.MinVersion)
+	}
+	return mb.Deploy(*api)
+}
+
+func history(ctx context.Context, api *smocker.API, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	session := fs.String("session", "", "session ID, the current session by default")
+	path := fs.String("path", "", "only calls to the path, may contain a query string")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	h, err := api.SessionHistory(ctx, *session)
+	if err != nil {
+		return err
+	}
+	if *path != "" {
+		h = h.FilterByPath(*path)
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h)
+}
+
+func verify(ctx context.Context, api *smocker.API, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	session := fs.String("session", "", "session ID, the current session by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	result, err := api.Verify(ctx, *session)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, result.Mocks.Message)
+	for _, m := range result.Mocks.Unused {
+		fmt.Fprintf(stdout, "unused mock: %s %s\n", m.Request.Method.Value, m.Request.Path.Value)
+	}
+	fmt.Fprintln(stdout, result.History.Message)
+	for _, e := range result.History.Failures {
+		fmt.Fprintf(stdout, "unmatched request: %s %s\n", e.Request.Method, e.Request.URL())
+	}
+	if !result.OK() {
+		return errVerifyFailed
+	}
+	return nil
+}
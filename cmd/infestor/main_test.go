@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	valid := write("valid.yaml", `
+reset: true
+exchanges:
+  - name: binance
+    symbol: ETH/BTC
+    price: 1
+`)
+	invalid := write("invalid.yaml", `
+exchanges:
+  - name: unknown
+    symbol: ETH/BTC
+`)
+	exchange := origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithPrice(1)
+	deploy := func(mb *infestor.MocksBuilder) func(t *testing.T, server *smocker.Server) {
+		return func(t *testing.T, server *smocker.Server) {
+			require.NoError(t, mb.Deploy(server.API()))
+		}
+	}
+	call := func(t *testing.T, server *smocker.Server, path string) {
+		resp, err := http.Get(server.URL() + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	mocks := func(t *testing.T, server *smocker.Server) []*smocker.Mock {
+		api := server.API()
+		m, err := api.Mocks(context.Background())
+		require.NoError(t, err)
+		return m
+	}
+
+	for _, tt := range []struct {
+		name  string
+		setup func(t *testing.T, server *smocker.Server)
+		args  []string
+		code  int
+		check func(t *testing.T, server *smocker.Server, stdout string)
+	}{
+		{
+			name: "deploy",
+			args: []string{"deploy", valid},
+			code: exitOK,
+			check: func(t *testing.T, server *smocker.Server, _ string) {
+				require.NotEmpty(t, mocks(t, server))
+			},
+		},
+		{
+			name:  "verify unused mocks",
+			setup: deploy(infestor.NewMocksBuilder().Add(exchange)),
+			args:  []string{"verify"},
+			code:  exitVerifyFailed,
+			check: func(t *testing.T, _ *smocker.Server, stdout string) {
+				require.Contains(t, stdout, "unused mock: GET /0/public/Ticker")
+			},
+		},
+		{
+			name: "verify used mocks",
+			setup: func(t *testing.T, server *smocker.Server) {
+				mock := smocker.NewMockBuilder().SetRequestPath(smocker.ShouldEqual("/ping")).Mock()
+				api := server.API()
+				require.NoError(t, api.AddMocks(context.Background(), []*smocker.Mock{mock}))
+				call(t, server, "/ping")
+			},
+			args: []string{"verify"},
+			code: exitOK,
+		},
+		{
+			name: "reset",
+			setup: func(t *testing.T, server *smocker.Server) {
+				deploy(infestor.NewMocksBuilder().Lock().Add(exchange))(t, server)
+				deploy(infestor.NewMocksBuilder().Add(origin.NewExchange("binance").WithSymbol("ETH/BTC")))(t, server)
+			},
+			args: []string{"reset"},
+			code: exitOK,
+			check: func(t *testing.T, server *smocker.Server, _ string) {
+				require.Empty(t, mocks(t, server))
+			},
+		},
+		{
+			name: "reset keeping locked mocks",
+			setup: func(t *testing.T, server *smocker.Server) {
+				deploy(infestor.NewMocksBuilder().Lock().Add(exchange))(t, server)
+				deploy(infestor.NewMocksBuilder().Add(origin.NewExchange("binance").WithSymbol("ETH/BTC")))(t, server)
+			},
+			args: []string{"reset", "-keep-locked"},
+			code: exitOK,
+			check: func(t *testing.T, server *smocker.Server, _ string) {
+				m := mocks(t, server)
+				require.NotEmpty(t, m)
+				for _, mock := range m {
+					require.True(t, mock.State.Locked)
+				}
+			},
+		},
+		{
+			name: "history",
+			setup: func(t *testing.T, server *smocker.Server) {
+				deploy(infestor.NewMocksBuilder().Add(exchange))(t, server)
+				call(t, server, "/0/public/Ticker?pair=XETHXXBT")
+				call(t, server, "/0/public/Time")
+			},
+			args: []string{"history", "-path", "/0/public/Ticker"},
+			code: exitOK,
+			check: func(t *testing.T, _ *smocker.Server, stdout string) {
+				var h smocker.History
+				require.NoError(t, json.Unmarshal([]byte(stdout), &h))
+				require.Len(t, h, 1)
+				require.Equal(t, "/0/public/Ticker", h[0].Request.Path)
+			},
+		},
+		{name: "invalid scenario", args: []string{"deploy", invalid}, code: exitError},
+		{name: "missing scenario", args: []string{"deploy", filepath.Join(dir, "missing.yaml")}, code: exitError},
+		{
+			name: "unreachable smocker",
+			args: []string{"-url", "http://127.0.0.1:1", "deploy", "-timeout", "100ms", valid},
+			code: exitError,
+		},
+		{name: "origins", args: []string{"origins"}, code: exitOK},
+		{name: "unknown command", args: []string{"unknown"}, code: exitError},
+		{name: "no command", code: exitError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := smocker.NewServer()
+			defer server.Close()
+			if tt.setup != nil {
+				tt.setup(t, server)
+			}
+
+			var stdout, stderr bytes.Buffer
+			// A later -url in tt.args overrides the server.
+			args := append([]string{"-url", server.AdminURL()}, tt.args...)
+			require.Equal(t, tt.code, run(args, &stdout, &stderr), stderr.String())
+			if tt.check != nil {
+				tt.check(t, server, stdout.String())
+			}
+		})
+	}
+}
+
+func TestDeployUnknownVersion(t *testing.T) {
+	server := smocker.NewServer()
+	defer server.Close()
+
+	// A development build of Smocker.
+	target, err := url.Parse(server.AdminURL())
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	dev := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/version" {
+			_, _ = w.Write([]byte(`{"app_name":"smocker","build_version":"dev"}`))
+			return
+		}
+		proxy.ServeHTTP(w, req)
+	}))
+	defer dev.Close()
+
+	scenario := filepath.Join(t.TempDir(), "scenario.yaml")
+	require.NoError(t, os.WriteFile(scenario, []byte(`
+exchanges:
+  - name: binance
+    symbol: ETH/BTC
+    price: 1
+`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitOK, run([]string{"-url", dev.URL, "deploy", scenario}, &stdout, &stderr), stderr.String())
+	require.Contains(t, stderr.String(), "warning: unknown smocker version")
+
+	api := server.API()
+	mocks, err := api.Mocks(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, mocks)
+}
@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestAggregatedTickers() {
+	err := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("binance").WithSymbol("BTC/USDT").WithPrice(2)).
+		Add(origin.NewExchange("huobi").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("huobi").WithSymbol("BTC/USDT").WithPrice(2)).
+		Add(origin.NewExchange("bitfinex").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("bitfinex").WithSymbol("BTC/USD").WithPrice(2)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	// Binance
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/24hr", s.url))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var binance []struct {
+		Symbol    string
+		LastPrice string
+	}
+	s.Require().NoError(parseBody(resp, &binance))
+	s.Require().Len(binance, 2)
+	s.Require().Equal("ETHBTC", binance[0].Symbol)
+	s.Require().Equal("BTCUSDT", binance[1].Symbol)
+	s.Require().Equal("2.00000000", binance[1].LastPrice)
+
+	// Huobi
+	resp, err = http.Get(fmt.Sprintf("%s/market/tickers", s.url))
+	s.Require().NoError(err)
+	var huobi struct {
+		Status string
+		Data   []struct {
+			Symbol string
+			Close  float64
+		}
+	}
+	s.Require().NoError(parseBody(resp, &huobi))
+	s.Require().Equal("ok", huobi.Status)
+	s.Require().Len(huobi.Data, 2)
+	s.Require().Equal("ethbtc", huobi.Data[0].Symbol)
+	s.Require().Equal("btcusdt", huobi.Data[1].Symbol)
+	s.Require().Equal(float64(2), huobi.Data[1].Close)
+
+	// Bitfinex
+	resp, err = http.Get(fmt.Sprintf("%s/v2/tickers?symbols=ALL", s.url))
+	s.Require().NoError(err)
+	var bitfinex [][]json.RawMessage
+	s.Require().NoError(parseBody(resp, &bitfinex))
+	s.Require().Len(bitfinex, 2)
+	s.Require().Equal(`"tETHBTC"`, string(bitfinex[0][0]))
+	s.Require().Equal(`"tBTCUSD"`, string(bitfinex[1][0]))
+}
+
+func (s *ExchangesE2ESuite) TestAggregatedTickersError() {
+	err := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("binance").WithSymbol("BTC/USDT").WithStatusCode(http.StatusInternalServerError)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/24hr", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,134 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func (s *ExchangesE2ESuite) TestCandles() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var candles []origin.Candle
+	for i := 0; i < 10; i++ {
+		p := float64(100 + i)
+		candles = append(candles, origin.NewCandle(start.Add(time.Duration(i)*time.Minute), p, p+0.5, p-0.5, p+0.25, 1))
+	}
+	mb := infestor.NewMocksBuilder().Reset()
+	for _, name := range []string{"binance", "kraken", "coinbase", "huobi"} {
+		mb.Add(origin.NewExchange(name).WithSymbol("ETH/BTC").WithPrice(1).WithCandles(time.Minute, candles...))
+	}
+	s.Require().NoError(mb.Deploy(s.api))
+
+	// Binance, oldest first.
+	var binance [][]any
+	for _, tt := range []struct {
+		query string
+		len   int
+		open  string
+		close string
+	}{
+		{"interval=1m", 10, "100.00000000", "100.25000000"},
+		{"interval=1m&limit=5", 5, "105.00000000", "105.25000000"},
+		{"interval=1m&limit=10", 10, "100.00000000", "100.25000000"},
+		{"interval=1m&limit=1000", 10, "100.00000000", "100.25000000"},
+		{"interval=5m", 2, "100.00000000", "104.25000000"},
+	} {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v3/klines?symbol=ETHBTC&%s", s.url, tt.query))
+		s.Require().NoError(err)
+		s.Require().NoError(parseBody(resp, &binance))
+		s.Require().Len(binance, tt.len, tt.query)
+		s.Require().Equal(tt.open, binance[0][1], tt.query)
+		s.Require().Equal(tt.close, binance[0][4], tt.query)
+	}
+	s.Require().Equal("99.50000000", binance[0][3])
+	s.Require().Equal("104.50000000", binance[0][2])
+	s.Require().Equal("5.00000000", binance[0][5])
+	s.Require().Equal(float64(start.Add(5*time.Minute).UnixMilli()-1), binance[0][6])
+
+	// Kraken, oldest first, 1 minute by default.
+	var kraken struct {
+		Result map[string]any
+	}
+	resp, err := http.Get(fmt.Sprintf("%s/0/public/OHLC?pair=XETHXXBT", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &kraken))
+	s.Require().Len(kraken.Result["XETHXXBT"], 10)
+	s.Require().Equal(float64(start.Add(9*time.Minute).Unix()), kraken.Result["last"])
+
+	kraken.Result = nil
+	resp, err = http.Get(fmt.Sprintf("%s/0/public/OHLC?pair=XETHXXBT&interval=5", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &kraken))
+	s.Require().Len(kraken.Result["XETHXXBT"], 2)
+
+	// Coinbase, newest first.
+	var coinbase [][]float64
+	resp, err = http.Get(fmt.Sprintf("%s/products/ETH-BTC/candles?granularity=300", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &coinbase))
+	s.Require().Len(coinbase, 2)
+	s.Require().Equal(float64(start.Add(5*time.Minute).Unix()), coinbase[0][0])
+	s.Require().Equal(109.25, coinbase[0][4])
+
+	// Huobi, newest first.
+	var huobi struct {
+		Ch   string
+		Data []struct {
+			ID    int64
+			Close float64
+		}
+	}
+	resp, err = http.Get(fmt.Sprintf("%s/market/history/kline?symbol=ethbtc&period=1min&size=5", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &huobi))
+	s.Require().Equal("market.ethbtc.kline.1min", huobi.Ch)
+	s.Require().Len(huobi.Data, 5)
+	s.Require().Equal(start.Add(9*time.Minute).Unix(), huobi.Data[0].ID)
+	s.Require().Equal(109.25, huobi.Data[0].Close)
+
+	// Intervals shorter than the series interval are not served.
+	ex := origin.NewExchange("coinbase").WithSymbol("ETH/BTC").WithCandles(time.Hour, candles...)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+	resp, err = http.Get(fmt.Sprintf("%s/products/ETH-BTC/candles?granularity=60", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().NotEqual(http.StatusOK, resp.StatusCode)
+}
+
+func (s *ExchangesE2ESuite) TestCandlesLimits() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var candles []origin.Candle
+	for i := 0; i < 600; i++ {
+		candles = append(candles, origin.NewCandle(start.Add(time.Duration(i)*time.Minute), 1, 1, 1, 1, 1))
+	}
+	ex := origin.NewExchange("binance").WithSymbol("ETH/BTC").WithCandles(time.Minute, candles...)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	for _, tt := range []struct {
+		query string
+		len   int
+	}{
+		{"", 500},
+		{"&limit=100", 100},
+		{"&limit=600", 600},
+		{"&limit=1000", 600},
+	} {
+		var binance [][]any
+		resp, err := http.Get(fmt.Sprintf("%s/api/v3/klines?symbol=ETHBTC&interval=1m%s", s.url, tt.query))
+		s.Require().NoError(err)
+		s.Require().NoError(parseBody(resp, &binance))
+		s.Require().Len(binance, tt.len, tt.query)
+	}
+
+	// Limits that are not mocked match no mock instead of serving the default number of candles.
+	for _, limit := range []int{3, 7, 499} {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v3/klines?symbol=ETHBTC&interval=1m&limit=%d", s.url, limit))
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().Equal(smocker.StatusNoMockFound, resp.StatusCode, limit)
+	}
+}
@@ -0,0 +1,153 @@
+package e2e
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) buildJSON(ex *origin.ExchangeMock) string {
+	mocks, err := infestor.NewMocksBuilder().Add(ex).Build()
+	s.Require().NoError(err)
+	b, err := json.Marshal(mocks)
+	s.Require().NoError(err)
+	return string(b)
+}
+
+func (s *ExchangesE2ESuite) TestContractUniswapV3() {
+	pool := types.MustAddressFromHex("0x109830a1AAaD605BbF02a9dFA7B0B92EC2FB7dAa")
+	wsteth := types.MustAddressFromHex("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0")
+	weth := types.MustAddressFromHex("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	sqrtPriceX96, _ := new(big.Int).SetString("84554395222218770838379633172", 10)
+
+	typed := origin.NewExchange("uniswapV3").
+		WithSymbol("WSTETH/WETH").
+		WithBlockNumber(100).
+		WithContract(
+			origin.UniswapV3Pool{
+				Address:                    pool,
+				Token0:                     wsteth,
+				Token1:                     weth,
+				SqrtPriceX96:               sqrtPriceX96,
+				Tick:                       1301,
+				ObservationIndex:           23,
+				ObservationCardinality:     150,
+				ObservationCardinalityNext: 150,
+			},
+			origin.ERC20Token{Address: wsteth, Symbol: "wstETH", Decimals: 18},
+			origin.ERC20Token{Address: weth, Symbol: "WETH", Decimals: 18},
+		)
+
+	raw := origin.NewExchange("uniswapV3").
+		WithSymbol("WSTETH/WETH").
+		WithCustom("blockNumber", 100).
+		WithFunctionData("slot0", []origin.FunctionData{{
+			Address: pool,
+			Args:    []any{},
+			Return: []any{
+				sqrtPriceX96, big.NewInt(1301), big.NewInt(23), big.NewInt(150), big.NewInt(150), 0, false,
+			},
+		}}).
+		WithFunctionData("token0", []origin.FunctionData{{Address: pool, Args: []any{}, Return: []any{wsteth}}}).
+		WithFunctionData("token1", []origin.FunctionData{{Address: pool, Args: []any{}, Return: []any{weth}}}).
+		WithFunctionData("symbols", []origin.FunctionData{
+			{Address: wsteth, Args: []any{}, Return: []any{"wstETH"}},
+			{Address: weth, Args: []any{}, Return: []any{"WETH"}},
+		}).
+		WithFunctionData("decimals", []origin.FunctionData{
+			{Address: wsteth, Args: []any{}, Return: []any{big.NewInt(18)}},
+			{Address: weth, Args: []any{}, Return: []any{big.NewInt(18)}},
+		})
+
+	s.Require().Equal(s.buildJSON(raw), s.buildJSON(typed))
+}
+
+func (s *ExchangesE2ESuite) TestContractCurveAndBalancer() {
+	pool := types.MustAddressFromHex("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022")
+	eth := types.MustAddressFromHex("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+	steth := types.MustAddressFromHex("0xae7ab96520de3a18e5e111b5eaab095312d7fe84")
+	price := big.NewInt(0.94 * 1e18)
+
+	typed := origin.NewExchange("curve").
+		WithSymbol("ETH/STETH").
+		WithBlockNumber(100).
+		WithContract(origin.CurvePool{
+			Address: pool,
+			Coins:   []types.Address{eth, steth},
+			Quotes:  []origin.CurveQuote{{I: 0, J: 1, Dx: big.NewInt(1e18), Dy: price}},
+		})
+	raw := origin.NewExchange("curve").
+		WithSymbol("ETH/STETH").
+		WithCustom("blockNumber", 100).
+		WithFunctionData("coins", []origin.FunctionData{
+			{Address: pool, Args: []any{0}, Return: []any{eth}},
+			{Address: pool, Args: []any{1}, Return: []any{steth}},
+		}).
+		WithFunctionData("get_dy1", []origin.FunctionData{
+			{Address: pool, Args: []any{0, 1, big.NewInt(1e18)}, Return: []any{price}},
+		})
+	s.Require().Equal(s.buildJSON(raw), s.buildJSON(typed))
+
+	balancerPool := types.MustAddressFromHex("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276")
+	reth := types.MustAddressFromHex("0xae78736Cd615f374D3085123A210448E74Fc6393")
+	typed = origin.NewExchange("balancerV2").
+		WithSymbol("RETH/WETH").
+		WithBlockNumber(100).
+		WithContract(origin.BalancerV2Pool{
+			Address:   balancerPool,
+			Price:     price,
+			RateCache: &origin.BalancerV2RateCache{Token: reth, Rate: big.NewInt(0.2 * 1e18)},
+		})
+	raw = origin.NewExchange("balancerV2").
+		WithSymbol("RETH/WETH").
+		WithCustom("blockNumber", 100).
+		WithFunctionData("getLatest", []origin.FunctionData{
+			{Address: balancerPool, Args: []any{byte(0)}, Return: []any{price}},
+		}).
+		WithFunctionData("getPriceRateCache", []origin.FunctionData{
+			{Address: balancerPool, Args: []any{reth}, Return: []any{big.NewInt(0.2 * 1e18), big.NewInt(0), big.NewInt(0)}},
+		})
+	s.Require().Equal(s.buildJSON(raw), s.buildJSON(typed))
+}
+
+func (s *ExchangesE2ESuite) TestContractValidation() {
+	pool := types.MustAddressFromHex("0x109830a1AAaD605BbF02a9dFA7B0B92EC2FB7dAa")
+	build := func(ex *origin.ExchangeMock) error {
+		_, err := infestor.NewMocksBuilder().Add(ex).Build()
+		return err
+	}
+
+	// Block number of a wrong type.
+	err := build(origin.NewExchange("dsr").
+		WithCustom("blockNumber", "100").
+		WithContract(origin.DSRPot{Address: pool, Rate: big.NewInt(1)}))
+	s.Require().ErrorContains(err, "block number must be an integer, got string")
+
+	// Missing value.
+	err = build(origin.NewExchange("dsr").
+		WithBlockNumber(100).
+		WithContract(origin.DSRPot{Address: pool}))
+	s.Require().ErrorContains(err, "invalid function data dsr[0]: return value #0 (value) must not be nil")
+
+	// Wrong number of slot0 values.
+	err = build(origin.NewExchange("uniswapV3").
+		WithBlockNumber(100).
+		WithFunctionData("slot0", []origin.FunctionData{{Address: pool, Return: []any{big.NewInt(1)}}}))
+	s.Require().ErrorContains(err, "slot0 returns 7 values, got 1")
+
+	// Value of a wrong type.
+	err = build(origin.NewExchange("curve").
+		WithBlockNumber(100).
+		WithFunctionData("coins", []origin.FunctionData{{Address: pool, Args: []any{"0"}, Return: []any{pool}}}))
+	s.Require().ErrorContains(err, "invalid function data coins[0]: argument #0 (index) must be int, got string")
+
+	// Function data of a wrong type.
+	err = build(origin.NewExchange("wsteth").
+		WithBlockNumber(100).
+		WithCustom("stEthPerToken", big.NewInt(1)))
+	s.Require().ErrorContains(err, "function data for stEthPerToken must be []FunctionData, got *big.Int")
+}
@@ -0,0 +1,54 @@
+package e2e
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestExactDecimals() {
+	price := origin.MustParseDecimal("0.000000012345")
+
+	// Binance emits 8 decimals by default.
+	ex := origin.NewExchange("binance").WithSymbol("BTC/USDT").WithPriceDecimal(price)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url)
+	var response struct {
+		Price string
+	}
+	resp, err := http.Get(url)
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().Equal("0.00000001", response.Price)
+
+	// All significant decimals.
+	ex = ex.WithDecimals(-1)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	resp, err = http.Get(url)
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().Equal("0.000000012345", response.Price)
+
+	// Fixed number of decimals, rounded.
+	ex = origin.NewExchange("coinbase").
+		WithSymbol("ETH/BTC").
+		WithPriceDecimal(origin.NewDecimalFromBigFloat(big.NewFloat(0.0523456789))).
+		WithVolume(0.1).
+		WithDecimals(9)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	resp, err = http.Get(fmt.Sprintf("%s/products/ETH-BTC/ticker", s.url))
+	s.Require().NoError(err)
+	var coinbase struct {
+		Price  string
+		Volume string
+	}
+	s.Require().NoError(parseBody(resp, &coinbase))
+	s.Require().Equal("0.052345679", coinbase.Price)
+	s.Require().Equal("0.100000000", coinbase.Volume)
+}
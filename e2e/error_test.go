@@ -0,0 +1,109 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestErrorResponses() {
+	for _, tt := range []struct {
+		name   string
+		ex     *origin.ExchangeMock
+		url    string
+		status int
+		body   string
+	}{
+		{
+			name:   "binance default",
+			ex:     origin.NewExchange("binance").WithSymbol("BTC/USDT").WithError(origin.ExchangeError{}),
+			url:    "/api/v3/ticker/price?symbol=BTCUSDT",
+			status: http.StatusBadRequest,
+			body:   `{"code":-1121,"msg":"Invalid symbol."}`,
+		},
+		{
+			name: "binance custom",
+			ex: origin.NewExchange("binance").WithSymbol("BTC/USDT").WithError(origin.ExchangeError{
+				StatusCode: http.StatusServiceUnavailable,
+				Code:       "-1001",
+				Message:    "Internal error; unable to process your request. Please try again.",
+			}),
+			url:    "/api/v3/ticker/price?symbol=BTCUSDT",
+			status: http.StatusServiceUnavailable,
+			body:   `{"code":-1001,"msg":"Internal error; unable to process your request. Please try again."}`,
+		},
+		{
+			name:   "kraken",
+			ex:     origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithError(origin.ExchangeError{}),
+			url:    "/0/public/Ticker?pair=ETHBTC",
+			status: http.StatusOK,
+			body:   `{"error":["EQuery:Unknown asset pair"]}`,
+		},
+		{
+			name:   "huobi",
+			ex:     origin.NewExchange("huobi").WithSymbol("ETH/BTC").WithError(origin.ExchangeError{}),
+			url:    "/market/detail/merged?symbol=ethbtc",
+			status: http.StatusOK,
+			body:   `{"status":"error","err-code":"invalid-parameter","err-msg":"invalid symbol","data":null}`,
+		},
+	} {
+		s.Run(tt.name, func() {
+			err := infestor.NewMocksBuilder().Reset().Add(tt.ex).Deploy(s.api)
+			s.Require().NoError(err)
+
+			resp, err := http.Get(s.url + tt.url)
+			s.Require().NoError(err)
+			defer func() { _ = resp.Body.Close() }()
+			s.Require().Equal(tt.status, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			s.Require().JSONEq(tt.body, string(body))
+		})
+	}
+
+	// Status code without an error keeps the body empty.
+	ex := origin.NewExchange("binance").WithSymbol("BTC/USDT").WithStatusCode(http.StatusNotFound)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url))
+	s.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Require().Empty(body)
+
+	// Binance codes are numbers.
+	ex = origin.NewExchange("binance").WithSymbol("BTC/USDT").WithError(origin.ExchangeError{Code: "invalid"})
+	s.Require().Error(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+}
+
+func (s *ExchangesE2ESuite) TestErrorResponseEthRPC() {
+	ex := origin.NewExchange("ethrpc").
+		WithCustom("blockNumber", 100).
+		WithError(origin.ExchangeError{Message: "header not found"})
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	reqJSON := `{"method":"eth_blockNumber","params":[],"id":42,"jsonrpc":"2.0"}`
+	resp, err := http.Post(fmt.Sprintf("%s/", s.url), "application/json", bytes.NewBufferString(reqJSON))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		ID    json.RawMessage `json:"id"`
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().Equal("42", string(response.ID))
+	s.Require().Equal(-32000, response.Error.Code)
+	s.Require().Equal("header not found", response.Error.Message)
+}
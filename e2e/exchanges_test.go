@@ -37,8 +37,9 @@ func TestExchangesE2ESuite(t *testing.T) {
 
 type ExchangesE2ESuite struct {
 	suite.Suite
-	api smocker.API
-	url string
+	api    smocker.API
+	url    string
+	server *smocker.Server
 }
 
 type jsonrpcMessage struct {
@@ -51,11 +52,27 @@ type jsonrpcMessage struct {
 
 func (s *ExchangesE2ESuite) SetupSuite() {
 	smockerHost, exist := os.LookupEnv("SMOCKER_HOST")
-	s.Require().True(exist, "SMOCKER_HOST env variable have to be set")
+	if !exist {
+		// No Smocker container available, running against in-process server.
+		s.server = smocker.NewServer()
+		s.api = s.server.API()
+		s.url = s.server.URL()
+		return
+	}
 
 	s.api = smocker.API{URL: smockerHost + ":8081"}
 
 	s.url = fmt.Sprintf("%s:8080", smockerHost)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	s.Require().NoError(s.api.WaitReady(ctx))
+}
+
+func (s *ExchangesE2ESuite) TearDownSuite() {
+	if s.server != nil {
+		s.server.Close()
+	}
 }
 
 func (s *ExchangesE2ESuite) SetupTest() {
@@ -293,6 +310,34 @@ func (s *ExchangesE2ESuite) TestBitfinex() {
 	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
 }
 
+func (s *ExchangesE2ESuite) TestBitfinexSymbols() {
+	for _, tt := range []struct {
+		symbol   string
+		expected string
+	}{
+		{"BTC/USD", "tBTCUSD"},
+		{"AVAX/USD", "tAVAX:USD"},
+		{"AVAX:/USD", "tAVAX:USD"},
+		{"TESTBTC/TESTUSD", "tTESTBTC:TESTUSD"},
+	} {
+		ex := origin.NewExchange("bitfinex").WithSymbol(tt.symbol).WithPrice(1)
+		err := infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api)
+		s.Require().NoError(err)
+
+		resp, err := http.Get(fmt.Sprintf("%s/v2/ticker/%s", s.url, tt.expected))
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode, tt.symbol)
+
+		resp, err = http.Get(fmt.Sprintf("%s/v2/tickers?symbols=ALL", s.url))
+		s.Require().NoError(err)
+		var tickers [][]any
+		s.Require().NoError(parseBody(resp, &tickers))
+		s.Require().Len(tickers, 1)
+		s.Require().Equal(tt.expected, tickers[0][0])
+	}
+}
+
 func (s *ExchangesE2ESuite) TestBitStamp() {
 	ts := time.Now()
 	ex := origin.NewExchange("bitstamp").
@@ -705,10 +750,10 @@ func (s *ExchangesE2ESuite) TestKraken() {
 
 	s.Require().NoError(err)
 	s.Require().Len(response.Result, 1)
-	s.Require().Equal("1.000000", response.Result["ETHBTC"].C[0])
-	s.Require().Equal("2.000000", response.Result["ETHBTC"].B[0])
-	s.Require().Equal("3.000000", response.Result["ETHBTC"].A[0])
-	s.Require().Equal("4.000000", response.Result["ETHBTC"].V[0])
+	s.Require().Equal("1.000000", response.Result["XETHXXBT"].C[0])
+	s.Require().Equal("2.000000", response.Result["XETHXXBT"].B[0])
+	s.Require().Equal("3.000000", response.Result["XETHXXBT"].A[0])
+	s.Require().Equal("4.000000", response.Result["XETHXXBT"].V[0])
 
 	// Test status code
 	ex = ex.WithStatusCode(http.StatusNotFound)
@@ -722,6 +767,76 @@ func (s *ExchangesE2ESuite) TestKraken() {
 	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
 }
 
+func (s *ExchangesE2ESuite) TestKrakenAssetNames() {
+	err := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("kraken").WithSymbol("BTC/USD").WithPrice(1)).
+		Add(origin.NewExchange("kraken").WithSymbol("DOT/USD").WithPrice(2)).
+		Add(origin.NewExchange("kraken").WithSymbol("DOGE/USD").WithPrice(3)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	for _, tt := range []struct {
+		pair string
+		key  string
+	}{
+		{"XBTUSD", "XXBTZUSD"},
+		{"XXBTZUSD", "XXBTZUSD"},
+		{"XBT/USD", "XXBTZUSD"},
+		{"BTCUSD", "XXBTZUSD"},
+		{"DOTUSD", "DOTUSD"},
+		{"XDGUSD", "XDGUSD"},
+		{"XDG/USD", "XDGUSD"},
+		{"DOGEUSD", "XDGUSD"},
+	} {
+		resp, err := http.Get(fmt.Sprintf("%s/0/public/Ticker?pair=%s", s.url, tt.pair))
+		s.Require().NoError(err)
+		var response struct {
+			Result map[string]any
+		}
+		s.Require().NoError(parseBody(resp, &response))
+		s.Require().Contains(response.Result, tt.key, tt.pair)
+	}
+
+	type assetPair struct {
+		Altname string
+		Wsname  string
+		Base    string
+		Quote   string
+	}
+	resp, err := http.Get(fmt.Sprintf("%s/0/public/AssetPairs", s.url))
+	s.Require().NoError(err)
+	var pairs struct {
+		Result map[string]assetPair
+	}
+	s.Require().NoError(parseBody(resp, &pairs))
+	s.Require().Len(pairs.Result, 3)
+	s.Require().Equal(assetPair{Altname: "XBTUSD", Wsname: "XBT/USD", Base: "XXBT", Quote: "ZUSD"}, pairs.Result["XXBTZUSD"])
+	s.Require().Equal(assetPair{Altname: "DOTUSD", Wsname: "DOT/USD", Base: "DOT", Quote: "ZUSD"}, pairs.Result["DOTUSD"])
+	s.Require().Equal(assetPair{Altname: "XDGUSD", Wsname: "XDG/USD", Base: "XXDG", Quote: "ZUSD"}, pairs.Result["XDGUSD"])
+
+	resp, err = http.Get(fmt.Sprintf("%s/0/public/AssetPairs?pair=DOTUSD", s.url))
+	s.Require().NoError(err)
+	pairs.Result = nil
+	s.Require().NoError(parseBody(resp, &pairs))
+	s.Require().Len(pairs.Result, 1)
+	s.Require().Contains(pairs.Result, "DOTUSD")
+
+	resp, err = http.Get(fmt.Sprintf("%s/0/public/Assets", s.url))
+	s.Require().NoError(err)
+	var assets struct {
+		Result map[string]struct {
+			Altname string
+		}
+	}
+	s.Require().NoError(parseBody(resp, &assets))
+	s.Require().Len(assets.Result, 4)
+	s.Require().Equal("XBT", assets.Result["XXBT"].Altname)
+	s.Require().Equal("XDG", assets.Result["XXDG"].Altname)
+	s.Require().Equal("USD", assets.Result["ZUSD"].Altname)
+	s.Require().Equal("DOT", assets.Result["DOT"].Altname)
+}
+
 func (s *ExchangesE2ESuite) TestKuCoin() {
 	ts := time.Now()
 	ex := origin.NewExchange("kucoin").
@@ -815,6 +930,89 @@ func (s *ExchangesE2ESuite) TestOkex() {
 	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
 }
 
+func (s *ExchangesE2ESuite) TestPoloniex() {
+	ts := time.Now()
+	ex := origin.NewExchange("poloniex").
+		WithSymbol("ETH/BTC").
+		WithPrice(1).
+		WithBid(2).
+		WithAsk(3).
+		WithVolume(4).
+		WithTime(ts)
+	other := origin.NewExchange("poloniex").
+		WithSymbol("BTC/USDT").
+		WithPrice(5)
+
+	err := infestor.NewMocksBuilder().Reset().Add(ex).Add(other).Deploy(s.api)
+	s.Require().NoError(err)
+
+	url := fmt.Sprintf("%s/markets/ETH_BTC/ticker24h", s.url)
+	resp, err := http.Get(url)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	type ticker struct {
+		Symbol   string
+		Close    string
+		Bid      string
+		Ask      string
+		Quantity string
+		TS       int64
+	}
+	var response ticker
+
+	err = parseBody(resp, &response)
+
+	s.Require().NoError(err)
+	s.Require().Equal("ETH_BTC", response.Symbol)
+	s.Require().Equal("1.000000", response.Close)
+	s.Require().Equal("2.000000", response.Bid)
+	s.Require().Equal("3.000000", response.Ask)
+	s.Require().Equal("4.000000", response.Quantity)
+	s.Require().Equal(ts.UnixMilli(), response.TS)
+
+	// All markets
+	resp, err = http.Get(fmt.Sprintf("%s/markets/ticker24h", s.url))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var tickers []ticker
+	err = parseBody(resp, &tickers)
+
+	s.Require().NoError(err)
+	s.Require().Len(tickers, 2)
+	s.Require().Equal("ETH_BTC", tickers[0].Symbol)
+	s.Require().Equal("BTC_USDT", tickers[1].Symbol)
+	s.Require().Equal("5.000000", tickers[1].Close)
+
+	// Legacy all markets
+	resp, err = http.Get(fmt.Sprintf("%s/public?command=returnTicker", s.url))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var legacy map[string]struct {
+		Last        string
+		QuoteVolume string
+	}
+	err = parseBody(resp, &legacy)
+
+	s.Require().NoError(err)
+	s.Require().Equal("1.000000", legacy["BTC_ETH"].Last)
+	s.Require().Equal("4.000000", legacy["BTC_ETH"].QuoteVolume)
+	s.Require().Equal("5.000000", legacy["USDT_BTC"].Last)
+
+	// Test status code
+	ex = ex.WithStatusCode(http.StatusNotFound)
+
+	err = infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api)
+	s.Require().NoError(err)
+
+	resp, err = http.Get(url)
+	s.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
 func (s *ExchangesE2ESuite) TestRocketPool() {
 	ex := origin.NewExchange("rocketpool").WithSymbol("RETH/ETH").WithPrice(1)
 	err := infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api)
@@ -1340,3 +1538,24 @@ func (s *ExchangesE2ESuite) TestWSTETH() {
 	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
 	defer func() { _ = resp.Body.Close() }()
 }
+
+func (s *ExchangesE2ESuite) TestEthRPCEchoesRequestID() {
+	const blockNumber int = 100
+	ex := origin.NewExchange("ethrpc").WithCustom("blockNumber", blockNumber)
+	err := infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api)
+	s.Require().NoError(err)
+
+	url := fmt.Sprintf("%s/", s.url)
+	for _, id := range []string{`42`, `"abc"`} {
+		reqJSON := fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"method":"eth_blockNumber","params":[]}`, id)
+		resp, err := http.Post(url, "application/json", bytes.NewBufferString(reqJSON))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var response jsonrpcMessage
+		err = parseBody(resp, &response)
+		s.Require().NoError(err)
+		s.Require().Equal(id, string(response.ID))
+		s.Require().Equal("64", response.Result)
+	}
+}
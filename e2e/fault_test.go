@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestFaults() {
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url)
+	get := func(fault origin.Fault) (*http.Response, string) {
+		ex := origin.NewExchange("binance").WithSymbol("BTC/USDT").WithPrice(1).WithFault(fault)
+		s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+		resp, err := http.Get(url)
+		s.Require().NoError(err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		return resp, string(body)
+	}
+
+	resp, body := get(origin.FaultTruncatedJSON)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().NotEmpty(body)
+	s.Require().False(json.Valid([]byte(body)))
+
+	resp, body = get(origin.FaultWrongContentType)
+	s.Require().Equal("text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+	s.Require().JSONEq(`{"symbol":"BTCUSDT","price":"1.00000000"}`, body)
+
+	resp, body = get(origin.FaultHTMLMaintenance)
+	s.Require().Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	s.Require().Contains(body, "<html>")
+
+	resp, body = get(origin.FaultEmptyBody)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Empty(body)
+
+	_, body = get(origin.FaultNaNPrice)
+	s.Require().JSONEq(`{"symbol":"BTCUSDT","price":"NaN"}`, body)
+
+	_, body = get(origin.FaultNegativePrice)
+	s.Require().JSONEq(`{"symbol":"BTCUSDT","price":"-1.00000000"}`, body)
+
+	_, body = get(origin.FaultZeroPrice)
+	s.Require().JSONEq(`{"symbol":"BTCUSDT","price":"0.00000000"}`, body)
+
+	_, body = get(origin.FaultMissingFields)
+	s.Require().JSONEq(`{"symbol":"BTCUSDT"}`, body)
+
+	_, body = get(origin.FaultExtraFields)
+	s.Require().JSONEq(`{"symbol":"BTCUSDT","price":"1.00000000","unknownField":"unexpected"}`, body)
+}
@@ -0,0 +1,31 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestHistory() {
+	mb := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithPrice(1))
+	err := mb.Deploy(s.api)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/price?symbol=ETHBTC", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+
+	history, err := mb.History(context.Background(), s.api, "binance")
+	s.Require().NoError(err)
+	s.Require().Len(history.FilterByPath("/api/v3/ticker/price?symbol=ETHBTC"), 1)
+
+	history, err = mb.History(context.Background(), s.api, "kraken")
+	s.Require().NoError(err)
+	s.Require().Empty(history)
+}
@@ -0,0 +1,39 @@
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestDelay() {
+	ex := origin.NewExchange("binance").
+		WithSymbol("BTC/USDT").
+		WithPrice(1).
+		WithDelay(100*time.Millisecond, 150*time.Millisecond)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	start := time.Now()
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+
+	// Dynamic responses are delayed too.
+	ex = origin.NewExchange("ethrpc").WithCustom("blockNumber", 100).WithDelay(100*time.Millisecond, 0)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	start = time.Now()
+	reqJSON := `{"method":"eth_blockNumber","params":[],"id":1,"jsonrpc":"2.0"}`
+	resp, err = http.Post(fmt.Sprintf("%s/", s.url), "application/json", bytes.NewBufferString(reqJSON))
+	s.Require().NoError(err)
+	var response jsonrpcMessage
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().NotEmpty(response.Result)
+	s.Require().GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+}
@@ -0,0 +1,73 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestLockedBaseline() {
+	const blockNumber int = 100
+	err := infestor.NewMocksBuilder().
+		Lock().
+		Add(origin.NewExchange("ethrpc").WithCustom("blockNumber", blockNumber)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	err = infestor.NewMocksBuilder().
+		Reset().
+		KeepLocked().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	chainID := func() int {
+		resp, err := http.Post(fmt.Sprintf("%s/", s.url), "application/json",
+			bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`))
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	s.Require().Equal(http.StatusOK, chainID())
+
+	mocks, err := s.api.Mocks(context.Background())
+	s.Require().NoError(err)
+	var ids []string
+	for _, m := range mocks {
+		if m.State.Locked {
+			ids = append(ids, m.State.ID)
+		}
+	}
+	s.Require().Len(ids, 3)
+
+	// Reset without KeepLocked clears locked mocks too.
+	err = infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+	s.Require().NotEqual(http.StatusOK, chainID())
+
+	err = infestor.NewMocksBuilder().
+		Lock().
+		Add(origin.NewExchange("ethrpc").WithCustom("blockNumber", blockNumber)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, chainID())
+	mocks, err = s.api.Mocks(context.Background())
+	s.Require().NoError(err)
+	ids = nil
+	for _, m := range mocks {
+		if m.State.Locked {
+			ids = append(ids, m.State.ID)
+		}
+	}
+	_, err = s.api.UnlockMocks(context.Background(), ids)
+	s.Require().NoError(err)
+	s.Require().NoError(s.api.ResetUnlocked(context.Background()))
+	s.Require().NotEqual(http.StatusOK, chainID())
+}
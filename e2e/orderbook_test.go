@@ -0,0 +1,115 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+type orderBookResponse struct {
+	Bids [][]any
+	Asks [][]any
+}
+
+func (s *ExchangesE2ESuite) TestOrderBook() {
+	book := origin.OrderBook{
+		Bids: []origin.OrderBookLevel{
+			origin.NewOrderBookLevel(0.9, 2),
+			origin.NewOrderBookLevel(0.95, 1),
+		},
+		Asks: []origin.OrderBookLevel{
+			origin.NewOrderBookLevel(1.1, 2),
+			origin.NewOrderBookLevel(1.05, 1),
+		},
+	}
+	mb := infestor.NewMocksBuilder().Reset()
+	for _, name := range []string{"binance", "kraken", "coinbase", "kucoin", "bitstamp"} {
+		mb.Add(origin.NewExchange(name).WithSymbol("ETH/BTC").WithPrice(1).WithOrderBook(book))
+	}
+	s.Require().NoError(mb.Deploy(s.api))
+
+	for _, tt := range []struct {
+		path string
+		bid  string
+		ask  string
+	}{
+		{"/api/v3/depth?symbol=ETHBTC", "0.95000000", "1.05000000"},
+		{"/products/ETH-BTC/book?level=2", "0.950000", "1.050000"},
+		{"/api/v1/market/orderbook/level2_20?symbol=ETH-BTC", "0.950000", "1.050000"},
+		{"/api/v3/market/orderbook/level2?symbol=ETH-BTC", "0.950000", "1.050000"},
+		{"/api/v2/order_book/ethbtc/", "0.950000", "1.050000"},
+	} {
+		resp, err := http.Get(s.url + tt.path)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode, tt.path)
+
+		var response struct {
+			orderBookResponse
+			Data orderBookResponse
+		}
+		s.Require().NoError(parseBody(resp, &response))
+		if len(response.Data.Bids) > 0 {
+			response.orderBookResponse = response.Data
+		}
+		s.Require().Len(response.Bids, 2, tt.path)
+		s.Require().Len(response.Asks, 2, tt.path)
+		s.Require().Equal(tt.bid, response.Bids[0][0], tt.path)
+		s.Require().Equal(tt.ask, response.Asks[0][0], tt.path)
+	}
+
+	// Kraken
+	resp, err := http.Get(fmt.Sprintf("%s/0/public/Depth?pair=XETHXXBT", s.url))
+	s.Require().NoError(err)
+	var kraken struct {
+		Result map[string]orderBookResponse
+	}
+	s.Require().NoError(parseBody(resp, &kraken))
+	s.Require().Len(kraken.Result["XETHXXBT"].Bids, 2)
+	s.Require().Equal("0.950000", kraken.Result["XETHXXBT"].Bids[0][0])
+	s.Require().Equal("1.050000", kraken.Result["XETHXXBT"].Asks[0][0])
+
+	// Coinbase level 1 has the best bid and ask only.
+	resp, err = http.Get(fmt.Sprintf("%s/products/ETH-BTC/book?level=1", s.url))
+	s.Require().NoError(err)
+	var coinbase orderBookResponse
+	s.Require().NoError(parseBody(resp, &coinbase))
+	s.Require().Len(coinbase.Bids, 1)
+	s.Require().Len(coinbase.Asks, 1)
+}
+
+func (s *ExchangesE2ESuite) TestOrderBookDepth() {
+	var book origin.OrderBook
+	for i := 1; i <= 25; i++ {
+		book.Bids = append(book.Bids, origin.NewOrderBookLevel(1-float64(i)/100, 1))
+		book.Asks = append(book.Asks, origin.NewOrderBookLevel(1+float64(i)/100, 1))
+	}
+	ex := origin.NewExchange("kucoin").WithSymbol("ETH/BTC").WithOrderBook(book).WithDecimals(2)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/market/orderbook/level2_20?symbol=ETH-BTC", s.url))
+	s.Require().NoError(err)
+	var response struct {
+		Data orderBookResponse
+	}
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().Len(response.Data.Bids, 20)
+	s.Require().Equal("0.99", response.Data.Bids[0][0])
+	s.Require().Equal("0.80", response.Data.Bids[19][0])
+
+	resp, err = http.Get(fmt.Sprintf("%s/api/v1/market/orderbook/level2_100?symbol=ETH-BTC", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().Len(response.Data.Bids, 25)
+
+	// Without an order book, depth endpoints are not mocked.
+	ex = origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	resp, err = http.Get(fmt.Sprintf("%s/api/v3/depth?symbol=ETHBTC", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(smocker.StatusNoMockFound, resp.StatusCode)
+}
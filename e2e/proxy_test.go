@@ -0,0 +1,42 @@
+package e2e
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func (s *ExchangesE2ESuite) TestProxy() {
+	if s.server == nil {
+		s.T().Skip("local upstream is not reachable from Smocker container")
+	}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "upstream %s?%s", r.URL.Path, r.URL.RawQuery)
+	}))
+	defer upstream.Close()
+
+	err := infestor.NewMocksBuilder().
+		Reset().
+		Proxy(smocker.ShouldMatch(".*"), upstream.URL).
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithStatusCode(http.StatusInternalServerError)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/price?symbol=ETHBTC", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	resp, err = http.Get(fmt.Sprintf("%s/products/ETH-BTC/ticker?level=1", s.url))
+	s.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Require().Equal("upstream /products/ETH-BTC/ticker?level=1", string(body))
+}
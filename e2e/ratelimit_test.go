@@ -0,0 +1,120 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestRateLimitBinance() {
+	ex := origin.NewExchange("binance").
+		WithSymbol("BTC/USDT").
+		WithPrice(1).
+		WithRateLimit(origin.RateLimit{After: 2})
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url)
+	for _, weight := range []string{"2", "4"} {
+		resp, err := http.Get(url)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		s.Require().Equal(weight, resp.Header.Get("X-MBX-USED-WEIGHT-1M"))
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(url)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusTooManyRequests, resp.StatusCode)
+		s.Require().Equal("60", resp.Header.Get("Retry-After"))
+		s.Require().Equal("6", resp.Header.Get("X-MBX-USED-WEIGHT-1M"))
+
+		var response struct {
+			Code int
+			Msg  string
+		}
+		s.Require().NoError(parseBody(resp, &response))
+		s.Require().Equal(-1003, response.Code)
+		s.Require().Contains(response.Msg, "Too many requests")
+	}
+
+	// IP ban.
+	ex = ex.WithRateLimit(origin.RateLimit{StatusCode: http.StatusTeapot, RetryAfter: 2 * time.Minute})
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	resp, err := http.Get(url)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusTeapot, resp.StatusCode)
+	s.Require().Equal("120", resp.Header.Get("Retry-After"))
+	var response struct {
+		Msg string
+	}
+	s.Require().NoError(parseBody(resp, &response))
+	s.Require().Contains(response.Msg, fmt.Sprintf("banned until %d", ex.Timestamp.Add(2*time.Minute).UnixMilli()))
+}
+
+func (s *ExchangesE2ESuite) TestRateLimit() {
+	// Kraken reports rate limits in the body.
+	ex := origin.NewExchange("kraken").
+		WithSymbol("ETH/BTC").
+		WithPrice(1).
+		WithRateLimit(origin.RateLimit{After: 1})
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	url := fmt.Sprintf("%s/0/public/Ticker?pair=ETHBTC", s.url)
+	resp, err := http.Get(url)
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(url)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().JSONEq(`{"error":["EAPI:Rate limit exceeded"]}`, string(body))
+
+	// Other origins respond with an empty 429.
+	ex = origin.NewExchange("coinbase").
+		WithSymbol("ETH/BTC").
+		WithPrice(1).
+		WithRateLimit(origin.RateLimit{After: 1, RetryAfter: 30 * time.Second})
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	url = fmt.Sprintf("%s/products/ETH-BTC/ticker", s.url)
+	resp, err = http.Get(url)
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(url)
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Require().Equal("30", resp.Header.Get("Retry-After"))
+}
+
+func (s *ExchangesE2ESuite) TestRateLimitVerify() {
+	ex := origin.NewExchange("binance").
+		WithSymbol("BTC/USDT").
+		WithPrice(1).
+		WithRateLimit(origin.RateLimit{After: 3})
+	mb := infestor.NewMocksBuilder().Reset().Add(ex)
+	s.Require().NoError(mb.Deploy(s.api))
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	// Clients don't have to reach the limit.
+	report, err := mb.Verify(context.Background(), s.api)
+	s.Require().NoError(err)
+	s.Require().True(report.OK(), report.String())
+}
@@ -0,0 +1,51 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func (s *ExchangesE2ESuite) TestRegisterOrigin() {
+	internal := origin.MockableFunc(func(e origin.ExchangeMock) (*smocker.Mock, error) {
+		return smocker.NewMockBuilder().
+			SetRequestMethod(smocker.ShouldEqual("GET")).
+			SetRequestPath(smocker.ShouldEqual(fmt.Sprintf("/price/%s", e.Symbol.Format("%s-%s")))).
+			SetResponseStatus(e.StatusCode).
+			SetResponseBody(fmt.Sprintf(`{"price":"%f"}`, e.Price)).
+			Mock(), nil
+	})
+	s.Require().NoError(origin.Register("internal", internal))
+	defer origin.Unregister("internal")
+
+	s.Require().Error(origin.Register("internal", internal))
+	s.Require().Error(origin.Register("binance", internal))
+	s.Require().Contains(origin.Names(), "internal")
+
+	err := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("internal").WithSymbol("ETH/USD").WithPrice(2)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(fmt.Sprintf("%s/price/ETH-USD", s.url))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		Price string
+	}
+	err = parseBody(resp, &response)
+	s.Require().NoError(err)
+	s.Require().Equal("2.000000", response.Price)
+
+	origin.Unregister("internal")
+	s.Require().NotContains(origin.Names(), "internal")
+	err = infestor.NewMocksBuilder().
+		Add(origin.NewExchange("internal").WithSymbol("ETH/USD").WithPrice(2)).
+		Deploy(s.api)
+	s.Require().Error(err)
+}
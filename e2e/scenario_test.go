@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/chronicleprotocol/infestor"
+)
+
+const scenarioYAML = `
+reset: true
+session: scenario
+exchanges:
+  - name: binance
+    symbol: ETH/BTC
+    price: 1
+  - name: coinbase
+    symbol: ETH/BTC
+    price: 2
+mocks:
+  - extra.yaml
+`
+
+const extraMocksYAML = `
+- request:
+    method: GET
+    path: /extra
+  response:
+    status: 200
+    body: extra
+`
+
+func (s *ExchangesE2ESuite) TestScenario() {
+	dir := s.T().TempDir()
+	path := filepath.Join(dir, "scenario.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte(scenarioYAML), 0600))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "extra.yaml"), []byte(extraMocksYAML), 0600))
+
+	scenario, err := infestor.LoadScenario(path)
+	s.Require().NoError(err)
+	mb, err := scenario.MocksBuilder()
+	s.Require().NoError(err)
+	s.Require().NoError(mb.Deploy(s.api))
+	s.Require().NotEmpty(mb.SessionID())
+
+	for _, path := range []string{
+		"/api/v3/ticker/price?symbol=ETHBTC",
+		"/products/ETH-BTC/ticker",
+		"/extra",
+	} {
+		resp, err := http.Get(fmt.Sprintf("%s%s", s.url, path))
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode, path)
+	}
+
+	_, err = infestor.LoadScenario(filepath.Join(dir, "missing.yaml"))
+	s.Require().Error(err)
+
+	scenario.Exchanges = append(scenario.Exchanges, infestor.ScenarioExchange{Name: "binance", Symbol: "ETHBTC"})
+	_, err = scenario.MocksBuilder()
+	s.Require().Error(err)
+}
@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestSequence() {
+	ex := origin.NewExchange("binance").
+		WithSymbol("BTC/USDT").
+		WithPrice(101).
+		WithSequence(
+			origin.Step{Times: 2, Apply: func(e *origin.ExchangeMock) { e.WithStatusCode(http.StatusInternalServerError) }},
+			origin.Step{Apply: func(e *origin.ExchangeMock) { e.WithPrice(100) }},
+		)
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=BTCUSDT", s.url)
+	for _, tt := range []struct {
+		status int
+		price  string
+	}{
+		{http.StatusInternalServerError, ""},
+		{http.StatusInternalServerError, ""},
+		{http.StatusOK, "100.00000000"},
+		{http.StatusOK, "101.00000000"},
+		{http.StatusOK, "101.00000000"},
+	} {
+		resp, err := http.Get(url)
+		s.Require().NoError(err)
+		s.Require().Equal(tt.status, resp.StatusCode)
+		if tt.status != http.StatusOK {
+			_ = resp.Body.Close()
+			continue
+		}
+		var response struct {
+			Price string
+		}
+		s.Require().NoError(parseBody(resp, &response))
+		s.Require().Equal(tt.price, response.Price)
+	}
+
+	// Steps keep the number of decimals of the exchange mock.
+	ex = origin.NewExchange("coinbase").
+		WithSymbol("ETH/BTC").
+		WithPrice(2).
+		WithDecimals(2).
+		WithSequence(origin.Step{Apply: func(e *origin.ExchangeMock) { e.WithPrice(1) }})
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	url = fmt.Sprintf("%s/products/ETH-BTC/ticker", s.url)
+	for _, price := range []string{"1.00", "2.00"} {
+		resp, err := http.Get(url)
+		s.Require().NoError(err)
+		var response struct {
+			Price string
+		}
+		s.Require().NoError(parseBody(resp, &response))
+		s.Require().Equal(price, response.Price)
+	}
+}
@@ -0,0 +1,44 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+)
+
+func (s *ExchangesE2ESuite) TestSession() {
+	first := infestor.NewMocksBuilder().
+		Reset().
+		Session("first").
+		Add(origin.NewExchange("gemini").WithSymbol("ETH/BTC").WithPrice(1))
+	err := first.Deploy(s.api)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(first.SessionID())
+
+	resp, err := http.Get(fmt.Sprintf("%s/v1/pubticker/ethbtc", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	second := infestor.NewMocksBuilder().
+		Session("second").
+		Add(origin.NewExchange("gemini").WithSymbol("ETH/BTC").WithPrice(2))
+	err = second.Deploy(s.api)
+	s.Require().NoError(err)
+
+	report, err := first.Verify(context.Background(), s.api)
+	s.Require().NoError(err)
+	s.Require().True(report.OK(), report.String())
+
+	report, err = second.Verify(context.Background(), s.api)
+	s.Require().NoError(err)
+	s.Require().Len(report.Unused, 1)
+
+	sessions, err := s.api.ListSessions(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2)
+	s.Require().Equal(second.SessionID(), sessions[1].ID)
+}
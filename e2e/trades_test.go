@@ -0,0 +1,173 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func (s *ExchangesE2ESuite) TestTrades() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []origin.Trade{
+		origin.NewTrade(start.Add(2*time.Second), 102, 0.5, origin.TradeSell),
+		origin.NewTrade(start, 100, 1, origin.TradeBuy),
+		origin.NewTrade(start.Add(time.Second), 101, 2, origin.TradeBuy),
+	}
+	mb := infestor.NewMocksBuilder().Reset()
+	for _, name := range []string{"binance", "kraken", "coinbase", "bitstamp"} {
+		mb.Add(origin.NewExchange(name).WithSymbol("ETH/BTC").WithPrice(1).WithTrades(trades...))
+	}
+	mb.Add(origin.NewExchange("binance").WithSymbol("BTC/USDT").WithPrice(1))
+	s.Require().NoError(mb.Deploy(s.api))
+
+	// Binance, oldest first.
+	var binance []struct {
+		ID           int64
+		Price        string
+		Qty          string
+		QuoteQty     string
+		Time         int64
+		IsBuyerMaker bool
+	}
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/trades?symbol=ETHBTC", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &binance))
+	s.Require().Len(binance, 3)
+	s.Require().Equal(int64(1), binance[0].ID)
+	s.Require().Equal("100.00000000", binance[0].Price)
+	s.Require().Equal("202.00000000", binance[1].QuoteQty)
+	s.Require().Equal(start.UnixMilli(), binance[0].Time)
+	s.Require().False(binance[0].IsBuyerMaker)
+	s.Require().True(binance[2].IsBuyerMaker)
+
+	resp, err = http.Get(fmt.Sprintf("%s/api/v3/trades?symbol=ETHBTC&limit=1", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &binance))
+	s.Require().Len(binance, 1)
+	s.Require().Equal("0.50000000", binance[0].Qty)
+
+	// Symbols without trades are not mocked.
+	resp, err = http.Get(fmt.Sprintf("%s/api/v3/trades?symbol=BTCUSDT", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Require().NotEqual(http.StatusOK, resp.StatusCode)
+
+	// Kraken, oldest first.
+	var kraken struct {
+		Result map[string]any
+	}
+	resp, err = http.Get(fmt.Sprintf("%s/0/public/Trades?pair=ETHXBT", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &kraken))
+	s.Require().Len(kraken.Result["XETHXXBT"], 3)
+	s.Require().Equal([]any{"100.000000", "1.000000", float64(start.Unix()), "b", "l", "", float64(1)},
+		kraken.Result["XETHXXBT"].([]any)[0])
+	s.Require().Equal(fmt.Sprint(start.Add(2*time.Second).UnixNano()), kraken.Result["last"])
+
+	for _, tt := range []struct {
+		count string
+		len   int
+	}{
+		{"1", 1},
+		{"5", 3},
+	} {
+		kraken.Result = nil
+		resp, err = http.Get(fmt.Sprintf("%s/0/public/Trades?pair=XETHXXBT&count=%s", s.url, tt.count))
+		s.Require().NoError(err)
+		s.Require().NoError(parseBody(resp, &kraken))
+		s.Require().Len(kraken.Result["XETHXXBT"], tt.len, tt.count)
+	}
+
+	// Coinbase, newest first with the maker side.
+	var coinbase []struct {
+		Time    time.Time
+		TradeID int64 `json:"trade_id"`
+		Price   string
+		Size    string
+		Side    string
+	}
+	resp, err = http.Get(fmt.Sprintf("%s/products/ETH-BTC/trades", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &coinbase))
+	s.Require().Len(coinbase, 3)
+	s.Require().Equal(int64(3), coinbase[0].TradeID)
+	s.Require().Equal("102.00000000", coinbase[0].Price)
+	s.Require().Equal("buy", coinbase[0].Side)
+	s.Require().Equal("sell", coinbase[2].Side)
+	s.Require().True(start.Equal(coinbase[2].Time))
+
+	// Bitstamp, newest first.
+	var bitstamp []map[string]string
+	resp, err = http.Get(fmt.Sprintf("%s/api/v2/transactions/ethbtc/?time=hour", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &bitstamp))
+	s.Require().Len(bitstamp, 3)
+	s.Require().Equal(map[string]string{
+		"date":   fmt.Sprint(start.Add(2 * time.Second).Unix()),
+		"tid":    "3",
+		"amount": "0.500000",
+		"price":  "102.000000",
+		"type":   "1",
+	}, bitstamp[0])
+}
+
+func (s *ExchangesE2ESuite) TestTradesLimits() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var trades []origin.Trade
+	for i := 0; i < 600; i++ {
+		trades = append(trades, origin.NewTrade(start.Add(time.Duration(i)*time.Second), 1, 1, origin.TradeBuy))
+	}
+	err := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithTrades(trades...)).
+		Add(origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithTrades(trades...)).
+		Deploy(s.api)
+	s.Require().NoError(err)
+
+	for _, tt := range []struct {
+		query string
+		len   int
+	}{
+		{"", 500},
+		{"&limit=100", 100},
+		{"&limit=1000", 600},
+	} {
+		var binance []map[string]any
+		resp, err := http.Get(fmt.Sprintf("%s/api/v3/trades?symbol=ETHBTC%s", s.url, tt.query))
+		s.Require().NoError(err)
+		s.Require().NoError(parseBody(resp, &binance))
+		s.Require().Len(binance, tt.len, tt.query)
+	}
+
+	// Limits that are not mocked match no mock instead of serving the default number of trades.
+	for _, path := range []string{
+		"/api/v3/trades?symbol=ETHBTC&limit=7",
+		"/api/v3/trades?symbol=ETHBTC&limit=499",
+		"/0/public/Trades?pair=XETHXXBT&count=7",
+	} {
+		resp, err := http.Get(s.url + path)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().Equal(smocker.StatusNoMockFound, resp.StatusCode, path)
+	}
+}
+
+func (s *ExchangesE2ESuite) TestTradesDecimals() {
+	ex := origin.NewExchange("bitstamp").
+		WithSymbol("ETH/BTC").
+		WithDecimals(2).
+		WithTrades(origin.NewTrade(time.Now(), 1.23456, 2, origin.TradeBuy))
+	s.Require().NoError(infestor.NewMocksBuilder().Reset().Add(ex).Deploy(s.api))
+
+	var bitstamp []map[string]string
+	resp, err := http.Get(fmt.Sprintf("%s/api/v2/transactions/ethbtc", s.url))
+	s.Require().NoError(err)
+	s.Require().NoError(parseBody(resp, &bitstamp))
+	s.Require().Len(bitstamp, 1)
+	s.Require().Equal("1.23", bitstamp[0]["price"])
+	s.Require().Equal("0", bitstamp[0]["type"])
+}
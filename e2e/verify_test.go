@@ -0,0 +1,117 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/infestor"
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func (s *ExchangesE2ESuite) TestVerify() {
+	mb := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("coinbase").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("kucoin").WithSymbol("ETH/BTC").WithPrice(1))
+	err := mb.Deploy(s.api)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(fmt.Sprintf("%s/products/ETH-BTC/ticker", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+
+	resp, err = http.Get(fmt.Sprintf("%s/products/BTC-USD/ticker", s.url))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+
+	report, err := mb.Verify(context.Background(), s.api)
+	s.Require().NoError(err)
+	s.Require().False(report.OK())
+
+	s.Require().Len(report.Unused, 1)
+	s.Require().Len(report.Unused[0].Origins, 1)
+	s.Require().Equal("kucoin", report.Unused[0].Origins[0].Name)
+	s.Require().Equal("ETH/BTC", report.Unused[0].Origins[0].Symbol.String())
+
+	s.Require().Len(report.Unmatched, 1)
+	s.Require().Equal("/products/BTC-USD/ticker", report.Unmatched[0].Request.Path)
+}
+
+func (s *ExchangesE2ESuite) TestVerifyAuxiliary() {
+	mb := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
+		Add(origin.NewExchange("binance").WithSymbol("BTC/USDT").WithPrice(2)).
+		Add(origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithPrice(1))
+	s.Require().NoError(mb.Deploy(s.api))
+
+	for _, path := range []string{
+		"/api/v3/ticker/price?symbol=ETHBTC",
+		"/api/v3/ticker/price?symbol=BTCUSDT",
+		"/0/public/Ticker?pair=XETHXXBT",
+	} {
+		resp, err := http.Get(s.url + path)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode, path)
+	}
+
+	report, err := mb.Verify(context.Background(), s.api)
+	s.Require().NoError(err)
+	s.Require().True(report.OK(), report.String())
+	s.Require().NotEmpty(report.UnusedAuxiliary)
+
+	// All-tickers mocks are built for every symbol.
+	for _, u := range report.UnusedAuxiliary {
+		if u.Mock.Request.Path.Value == "/api/v3/ticker/24hr" {
+			s.Require().Len(u.Origins, 2)
+			return
+		}
+	}
+	s.Fail("no unused 24hr ticker mock")
+}
+
+func (s *ExchangesE2ESuite) TestVerifyClient() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var candles []origin.Candle
+	for i := 0; i < 30; i++ {
+		candles = append(candles, origin.NewCandle(start.Add(time.Duration(i)*time.Minute), 1, 1, 1, 1, 1))
+	}
+	book := origin.OrderBook{
+		Bids: []origin.OrderBookLevel{origin.NewOrderBookLevel(0.9, 1)},
+		Asks: []origin.OrderBookLevel{origin.NewOrderBookLevel(1.1, 1)},
+	}
+	mb := infestor.NewMocksBuilder().
+		Reset().
+		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1).WithCandles(time.Minute, candles...)).
+		Add(origin.NewExchange("coinbase").WithSymbol("ETH/BTC").WithPrice(1).WithOrderBook(book)).
+		Add(origin.NewExchange("kucoin").WithSymbol("ETH/BTC").WithPrice(1).WithOrderBook(book)).
+		Add(origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithPrice(1).WithSequence(
+			origin.Step{Apply: func(e *origin.ExchangeMock) { e.WithStatusCode(http.StatusInternalServerError) }},
+		))
+	s.Require().NoError(mb.Deploy(s.api))
+
+	// A client calls each endpoint it uses once, with default parameters.
+	for _, path := range []string{
+		"/api/v3/ticker/price?symbol=ETHBTC",
+		"/api/v3/klines?symbol=ETHBTC&interval=1m",
+		"/products/ETH-BTC/ticker",
+		"/products/ETH-BTC/book",
+		"/api/v1/market/orderbook/level1?symbol=ETH-BTC",
+		"/api/v1/market/orderbook/level2_20?symbol=ETH-BTC",
+		"/0/public/Ticker?pair=XETHXXBT",
+	} {
+		resp, err := http.Get(s.url + path)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Require().NotEqual(smocker.StatusNoMockFound, resp.StatusCode, path)
+	}
+
+	report, err := mb.Verify(context.Background(), s.api)
+	s.Require().NoError(err)
+	s.Require().True(report.OK(), report.String())
+	s.Require().NotEmpty(report.UnusedAuxiliary)
+}
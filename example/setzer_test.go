@@ -1,9 +1,11 @@
 package example
 
 import (
+	"context"
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/chronicleprotocol/infestor"
 	"github.com/chronicleprotocol/infestor/origin"
@@ -23,6 +25,10 @@ func callSetzer(params ...string) (string, error) {
 func TestETHBTC(t *testing.T) {
 	api := smocker.API{URL: "http://smocker:8081"}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, api.WaitReady(ctx))
+
 	err := infestor.NewMocksBuilder().
 		Reset().
 		Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1)).
@@ -30,7 +36,7 @@ func TestETHBTC(t *testing.T) {
 		Add(origin.NewExchange("coinbase").WithSymbol("ETH/BTC").WithPrice(1)).
 		Add(origin.NewExchange("huobi").WithSymbol("ETH/BTC").WithPrice(1)).
 		Add(origin.NewExchange("poloniex").WithSymbol("ETH/BTC").WithPrice(1)).
-		Add(origin.NewExchange("kraken").WithSymbol("XETH/XXBT").WithPrice(1)).
+		Add(origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithPrice(1)).
 		Deploy(api)
 
 	// Build your test further
@@ -4,16 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/chronicleprotocol/infestor/origin"
 	"github.com/chronicleprotocol/infestor/smocker"
 )
 
 type MocksBuilder struct {
-	debug bool
-	reset bool
-	mocks map[string][]origin.ExchangeMock
+	debug      bool
+	reset      bool
+	keepLocked bool
+	lock       bool
+	session    string
+	sessionID  string
+	raw        []*smocker.Mock
+	mocks      map[string][]origin.ExchangeMock
+	// names are names of exchanges in the order they were first added.
+	names []string
 }
 
 func NewMocksBuilder() *MocksBuilder {
@@ -24,7 +30,7 @@ func NewMocksBuilder() *MocksBuilder {
 	}
 }
 
-// Debug sets debug flag and mocks yaml file will be created
+// Debug sets debug flag and mocks.yaml file will be created in the working directory on Deploy
 func (mb *MocksBuilder) Debug() *MocksBuilder {
 	mb.debug = true
 	return mb
@@ -36,11 +42,52 @@ func (mb *MocksBuilder) Reset() *MocksBuilder {
 	return mb
 }
 
+// KeepLocked makes Reset keep locked mocks, see Lock.
+func (mb *MocksBuilder) KeepLocked() *MocksBuilder {
+	mb.keepLocked = true
+	return mb
+}
+
+// Lock deploys mocks as locked, so they survive resets. Useful for baseline mocks
+// shared by several suites, e.g. ethrpc chainId/blockNumber.
+func (mb *MocksBuilder) Lock() *MocksBuilder {
+	mb.lock = true
+	return mb
+}
+
+// Session makes Deploy start a new smocker session with given name and push mocks into it,
+// so history and verification can be inspected per session.
+func (mb *MocksBuilder) Session(name string) *MocksBuilder {
+	mb.session = name
+	return mb
+}
+
+// SessionID returns the ID of the session started by Deploy, empty if no session was started.
+func (mb *MocksBuilder) SessionID() string {
+	return mb.sessionID
+}
+
+// Proxy forwards requests with matching path to the given host instead of mocking them.
+// Exchange mocks take precedence over proxies, so `Proxy(smocker.ShouldMatch(".*"), host)`
+// lets everything that is not mocked flow to the host.
+func (mb *MocksBuilder) Proxy(path smocker.StringMatcher, host string) *MocksBuilder {
+	mb.raw = append(mb.raw, smocker.NewMockBuilder().SetRequestPath(path).SetProxy(host).Mock())
+	return mb
+}
+
+// AddMocks adds prebuilt smocker mocks, e.g. loaded with smocker.LoadMocks.
+// Exchange mocks take precedence over them.
+func (mb *MocksBuilder) AddMocks(mocks ...*smocker.Mock) *MocksBuilder {
+	mb.raw = append(mb.raw, mocks...)
+	return mb
+}
+
 // Add adds new exchange/pair mock to smoker
 func (mb *MocksBuilder) Add(e *origin.ExchangeMock) *MocksBuilder {
 	mocks, ok := mb.mocks[e.Name]
 	if !ok {
 		mb.mocks[e.Name] = []origin.ExchangeMock{*e}
+		mb.names = append(mb.names, e.Name)
 		return mb
 	}
 
@@ -48,39 +95,158 @@ func (mb *MocksBuilder) Add(e *origin.ExchangeMock) *MocksBuilder {
 	return mb
 }
 
-func (mb *MocksBuilder) Deploy(api smocker.API) error {
-	ctx := context.Background()
-	var result []*smocker.Mock
-	for name, mocks := range mb.mocks {
-		part, err := origin.BuildMocksForExchanges(name, mocks)
+// Build builds smocker mocks for all added exchanges, proxies and prebuilt mocks, in the order they are deployed.
+// Exchanges are built in the order they were first added, so the same builder always builds the same mocks.
+func (mb *MocksBuilder) Build() ([]*smocker.Mock, error) {
+	// Smocker gives precedence to mocks added later, so proxies and prebuilt mocks go first.
+	result := append([]*smocker.Mock{}, mb.raw...)
+	for _, name := range mb.names {
+		part, err := origin.BuildMocksForExchanges(name, mb.mocks[name])
 		if err != nil {
-			return err
+			return nil, err
 		}
 		result = append(result, part...)
 	}
+	return result, nil
+}
 
-	body, err := json.Marshal(result)
+// Export writes built mocks to the file in Smocker format. Files with `.json`
+// extension are written as JSON, anything else as YAML.
+func (mb *MocksBuilder) Export(path string) error {
+	mocks, err := mb.Build()
 	if err != nil {
-		return fmt.Errorf("failed to build mocks body: %w", err)
+		return err
 	}
+	return smocker.SaveMocks(path, mocks)
+}
+
+func (mb *MocksBuilder) Deploy(api smocker.API) error {
+	ctx := context.Background()
+	result, err := mb.Build()
+	if err != nil {
+		return err
+	}
+
 	if mb.debug {
-		err := os.WriteFile("./mocks.json", body, 0644) //nolint:gosec,gomnd
+		err := smocker.SaveMocks("./mocks.yaml", result)
 		if err != nil {
 			return fmt.Errorf("failed to write debug mocks.yaml: %w", err)
 		}
 	}
 
 	if mb.reset {
-		err := api.Reset(ctx)
+		reset := api.Reset
+		if mb.keepLocked {
+			reset = api.ResetUnlocked
+		}
+		err := reset(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to reset mocks before pushing new: %w", err)
 		}
 	}
 
+	if mb.session != "" {
+		session, err := api.StartSession(ctx, mb.session)
+		if err != nil {
+			return fmt.Errorf("failed to start session %s: %w", mb.session, err)
+		}
+		mb.sessionID = session.ID
+	}
+
 	// If we don't have mocks available - do nothing...
-	if len(mb.mocks) == 0 {
+	if len(result) == 0 {
 		return nil
 	}
 
-	return api.AddMocks(ctx, result)
+	err = api.AddSessionMocks(ctx, mb.sessionID, result)
+	if err != nil {
+		return err
+	}
+	if mb.lock {
+		return mb.lockDeployed(ctx, api, result)
+	}
+	return nil
+}
+
+// lockDeployed locks the deployed mocks matching the given ones. Mocks are matched
+// by content, as other clients may add mocks to the same session meanwhile.
+func (mb *MocksBuilder) lockDeployed(ctx context.Context, api smocker.API, mocks []*smocker.Mock) error {
+	deployed, err := api.SessionMocks(ctx, mb.sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get deployed mocks: %w", err)
+	}
+	pending := make(map[string]int, len(mocks))
+	for _, m := range mocks {
+		key, err := mockKey(m)
+		if err != nil {
+			return err
+		}
+		pending[key]++
+	}
+	// Smocker lists the most recently added mocks first, so identical mocks
+	// added earlier are not locked instead of ours.
+	ids := make([]string, 0, len(mocks))
+	for _, m := range deployed {
+		if m.State == nil || m.State.Locked {
+			continue
+		}
+		key, err := mockKey(m)
+		if err != nil {
+			return err
+		}
+		if pending[key] > 0 {
+			pending[key]--
+			ids = append(ids, m.State.ID)
+		}
+	}
+	if len(ids) != len(mocks) {
+		return fmt.Errorf("failed to lock mocks: found %d of %d deployed mocks", len(ids), len(mocks))
+	}
+	_, err = api.LockMocks(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to lock mocks: %w", err)
+	}
+	return nil
+}
+
+// mockKey returns the JSON encoding of the mock without its state, normalized by
+// a round trip, so deployed mocks can be compared with built ones.
+func mockKey(m *smocker.Mock) (string, error) {
+	c := *m
+	c.State = nil
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mock: %w", err)
+	}
+	var n smocker.Mock
+	if err := json.Unmarshal(b, &n); err != nil {
+		return "", fmt.Errorf("failed to unmarshal mock: %w", err)
+	}
+	b, err = json.Marshal(n)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mock: %w", err)
+	}
+	return string(b), nil
+}
+
+// History returns the calls received by smocker that were addressed to the given exchange,
+// i.e. calls that match any of the mocks built for it. If Deploy started a session, only
+// calls from that session are returned.
+func (mb *MocksBuilder) History(ctx context.Context, api smocker.API, exchangeName string) (smocker.History, error) {
+	mocks, err := origin.BuildMocksForExchanges(exchangeName, mb.mocks[exchangeName])
+	if err != nil {
+		return nil, err
+	}
+	history, err := api.SessionHistory(ctx, mb.sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	return history.Filter(func(e *smocker.HistoryEntry) bool {
+		for _, m := range mocks {
+			if m.Request.Match(e.Request.HTTPRequest(), string(e.Request.Body)) {
+				return true
+			}
+		}
+		return false
+	}), nil
 }
@@ -0,0 +1,63 @@
+package infestor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func TestLockDeployedIgnoresOtherMocks(t *testing.T) {
+	ctx := context.Background()
+	server := smocker.NewServer()
+	defer server.Close()
+	api := server.API()
+
+	mb := NewMocksBuilder().Lock().Add(origin.NewExchange("binance").WithSymbol("ETH/BTC").WithPrice(1))
+	mocks, err := mb.Build()
+	require.NoError(t, err)
+	require.NoError(t, api.AddMocks(ctx, mocks))
+
+	// Another suite sharing Smocker adds its mocks before ours are locked.
+	other, err := NewMocksBuilder().Add(origin.NewExchange("kraken").WithSymbol("ETH/BTC").WithPrice(1)).Build()
+	require.NoError(t, err)
+	require.NoError(t, api.AddMocks(ctx, other))
+
+	require.NoError(t, mb.lockDeployed(ctx, api, mocks))
+
+	deployed, err := api.Mocks(ctx)
+	require.NoError(t, err)
+	locked := 0
+	for _, m := range deployed {
+		if m.State.Locked {
+			locked++
+			require.NotEqual(t, "/0/public/Ticker", m.Request.Path.Value)
+		}
+	}
+	require.Equal(t, len(mocks), locked)
+}
+
+func TestExportIsDeterministic(t *testing.T) {
+	ts := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	export := func(path string) []byte {
+		mb := NewMocksBuilder()
+		for _, name := range []string{"binance", "kraken", "coinbase", "kucoin", "bitstamp", "huobi"} {
+			mb.Add(origin.NewExchange(name).WithSymbol("ETH/BTC").WithPrice(1).WithTime(ts))
+		}
+		require.NoError(t, mb.Export(path))
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return b
+	}
+	dir := t.TempDir()
+	want := export(filepath.Join(dir, "mocks.yaml"))
+	for i := 0; i < 10; i++ {
+		require.Equal(t, string(want), string(export(filepath.Join(dir, "again.yaml"))))
+	}
+}
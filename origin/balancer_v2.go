@@ -9,7 +9,6 @@ import (
 
 	"github.com/chronicleprotocol/infestor/smocker"
 	"github.com/defiweb/go-eth/abi"
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -43,66 +42,53 @@ func (b BalancerV2) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 
 func (b BalancerV2) buildGetLatest(e ExchangeMock) (*smocker.Mock, error) {
 	// cast sig "getLatest(uint8)(uint256)" == 0xb10be739
-	blockNumber, ok := e.Custom["blockNumber"].(int) // Should use same block number with EthRPC exchange
-	if !ok {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber() // Should use same block number with EthRPC exchange
+	if err != nil {
+		return nil, err
 	}
-	funcData, ok := e.Custom["getLatest"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
+	funcData, err := e.functionData("getLatest")
+	if err != nil {
+		return nil, err
+	}
+	if len(funcData) < 1 {
 		return nil, fmt.Errorf("not found function data for getLatest")
 	}
 
 	var calls []MultiCall
+	var data []any
 	for i := 0; i < len(funcData); i++ {
-		data, _ := getLatest.EncodeArgs(funcData[i].Args[0].(byte))
+		getLatestData, price, err := encodeGetLatest(funcData[i])
+		if err != nil {
+			return nil, functionDataError("getLatest", i, err)
+		}
 		calls = append(calls, MultiCall{
 			Target: funcData[i].Address,
-			Data:   data,
+			Data:   getLatestData,
 		})
-	}
-	args, _ := encodeMultiCallArgs(calls)
-	var data []any
-	for i := 0; i < len(funcData); i++ {
-		price := funcData[i].Return[0].(*big.Int)
 		data = append(data, types.Bytes(price.Bytes()).PadLeft(32))
 	}
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+	return multiCallMock(e, blockNumber, calls, data)
 }
 
 func (b BalancerV2) buildWithGetPriceRateCache(e ExchangeMock) (*smocker.Mock, error) {
 	// cast sig "getPriceRateCache(address)(uint256,uint256,uint256)" == 0xb867ee5a
 	//                                     rate uint256, duration uint256, expires uint256
-	blockNumber, ok := e.Custom["blockNumber"].(int) // Should use same block number with EthRPC exchange
-	if !ok {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber() // Should use same block number with EthRPC exchange
+	if err != nil {
+		return nil, err
 	}
-	getLatestFuncData, ok := e.Custom["getLatest"].([]FunctionData)
-	if !ok || len(getLatestFuncData) < 1 {
+	getLatestFuncData, err := e.functionData("getLatest")
+	if err != nil {
+		return nil, err
+	}
+	if len(getLatestFuncData) < 1 {
 		return nil, fmt.Errorf("not found function data for getLatest")
 	}
-	getPriceRateCacheFuncData, ok := e.Custom["getPriceRateCache"].([]FunctionData)
-	if !ok || len(getPriceRateCacheFuncData) < 1 {
+	getPriceRateCacheFuncData, err := e.functionData("getPriceRateCache")
+	if err != nil {
+		return nil, err
+	}
+	if len(getPriceRateCacheFuncData) < 1 {
 		return nil, nil
 	}
 	if len(getLatestFuncData) != len(getPriceRateCacheFuncData) {
@@ -112,9 +98,14 @@ func (b BalancerV2) buildWithGetPriceRateCache(e ExchangeMock) (*smocker.Mock, e
 	var calls []MultiCall
 	var data []any
 	for i := 0; i < len(getLatestFuncData); i++ {
-		getLatestData, _ := getLatest.EncodeArgs(getLatestFuncData[i].Args[0].(byte))
-		getPriceRateCacheData, _ := getPriceRateCache.EncodeArgs(
-			getPriceRateCacheFuncData[i].Args[0].(types.Address))
+		getLatestData, price, err := encodeGetLatest(getLatestFuncData[i])
+		if err != nil {
+			return nil, functionDataError("getLatest", i, err)
+		}
+		getPriceRateCacheData, rateCache, err := encodeGetPriceRateCache(getPriceRateCacheFuncData[i])
+		if err != nil {
+			return nil, functionDataError("getPriceRateCache", i, err)
+		}
 		calls = append(calls, MultiCall{
 			Target: getLatestFuncData[i].Address,
 			Data:   getLatestData,
@@ -122,34 +113,88 @@ func (b BalancerV2) buildWithGetPriceRateCache(e ExchangeMock) (*smocker.Mock, e
 			Target: getPriceRateCacheFuncData[i].Address,
 			Data:   getPriceRateCacheData,
 		})
-		price := getLatestFuncData[i].Return[0].(*big.Int)
-		rate := getPriceRateCacheFuncData[i].Return[0].(*big.Int)
-		duration := getPriceRateCacheFuncData[i].Return[1].(*big.Int)
-		expires := getPriceRateCacheFuncData[i].Return[2].(*big.Int)
-		data = append(data, types.Bytes(price.Bytes()).PadLeft(32),
-			abi.MustEncodeValues(getPriceRateCache.Outputs(), rate, duration, expires))
-	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+		data = append(data, types.Bytes(price.Bytes()).PadLeft(32), rateCache)
+	}
+	return multiCallMock(e, blockNumber, calls, data)
+}
+
+func encodeGetLatest(f FunctionData) ([]byte, *big.Int, error) {
+	variable, err := argAt[byte](f, 0, "variable")
+	if err != nil {
+		return nil, nil, err
+	}
+	price, err := returnAt[*big.Int](f, 0, "price")
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := getLatest.EncodeArgs(variable)
+	if err != nil {
+		return nil, nil, err
+	}
+	return args, price, nil
+}
+
+func encodeGetPriceRateCache(f FunctionData) ([]byte, []byte, error) {
+	token, err := argAt[types.Address](f, 0, "token")
+	if err != nil {
+		return nil, nil, err
+	}
+	rate, err := returnAt[*big.Int](f, 0, "rate")
+	if err != nil {
+		return nil, nil, err
+	}
+	duration, err := returnAt[*big.Int](f, 1, "duration")
+	if err != nil {
+		return nil, nil, err
+	}
+	expires, err := returnAt[*big.Int](f, 2, "expires")
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := getPriceRateCache.EncodeArgs(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := abi.EncodeValues(getPriceRateCache.Outputs(), rate, duration, expires)
+	if err != nil {
+		return nil, nil, err
+	}
+	return args, res, nil
+}
+
+// BalancerV2Pool is a Balancer V2 pool and its latest price. If RateCache is set,
+// it has to be set for all pools of the exchange mock.
+type BalancerV2Pool struct {
+	Address types.Address
+	// Variable is the `getLatest` argument, 0 is the pair price.
+	Variable  byte
+	Price     *big.Int
+	RateCache *BalancerV2RateCache
+}
+
+// BalancerV2RateCache is the rate of the token returned by `getPriceRateCache`.
+// Nil Duration and Expires are zero.
+type BalancerV2RateCache struct {
+	Token    types.Address
+	Rate     *big.Int
+	Duration *big.Int
+	Expires  *big.Int
+}
+
+func (p BalancerV2Pool) FunctionData() map[string][]FunctionData {
+	res := map[string][]FunctionData{
+		"getLatest": {{
+			Address: p.Address,
+			Args:    []any{p.Variable},
+			Return:  []any{p.Price},
+		}},
+	}
+	if p.RateCache != nil {
+		res["getPriceRateCache"] = []FunctionData{{
+			Address: p.Address,
+			Args:    []any{p.RateCache.Token},
+			Return:  []any{p.RateCache.Rate, zeroIfNil(p.RateCache.Duration), zeroIfNil(p.RateCache.Expires)},
+		}}
+	}
+	return res
 }
@@ -2,6 +2,10 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -13,30 +17,93 @@ func (b Binance) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	if err != nil {
 		return nil, err
 	}
-	tickers, err := CombineMocks(e, b.buildWholeDay)
+	tickers, err := AggregateMocks(e, b.buildWholeDay)
 	if err != nil {
 		return nil, err
 	}
-	return append(mocksOne, tickers...), nil
+	depth, err := CombineMocks(e, b.buildDepth)
+	if err != nil {
+		return nil, err
+	}
+	klines, err := buildCandleMocks(e, binanceIntervals, true, b.buildKlines)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := buildLimitMocks(e, true, b.buildTrades)
+	if err != nil {
+		return nil, err
+	}
+	mocks := append(mocksOne, auxiliary(tickers)...)
+	mocks = append(mocks, depth...)
+	mocks = append(mocks, klines...)
+	return append(mocks, trades...), nil
 }
 
-func (b Binance) buildWholeDay(e ExchangeMock) (*smocker.Mock, error) {
-	symbol := e.Symbol.Format("%s%s")
-	body := `[{"symbol":"%s","lastPrice":"%.8f","bidPrice":"%.8f","askPrice":"%.8f","volume":"%.8f","closeTime":%d}]`
+func (b Binance) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusBadRequest, "-1121", "Invalid symbol.")
+	code, err := ee.numericCode()
+	if err != nil {
+		return err
+	}
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"code":%d,"msg":%s}`, code, jsonString(ee.Message)))
+	return nil
+}
+
+// binanceRequestWeight is the request weight of ticker endpoints for a single symbol.
+const binanceRequestWeight = 2
 
+// BuildRateLimitResponse adds used weight headers to all responses. Rate limited
+// responses with 418 report an IP ban.
+func (b Binance) BuildRateLimitResponse(e ExchangeMock, m *smocker.Mock, n uint) error {
+	rl := e.RateLimit.withDefaults()
+	if rl.limited(n) {
+		msg := "Too many requests; current limit of IP(127.0.0.1) is 6000 requests per minute."
+		if rl.StatusCode == http.StatusTeapot {
+			bannedUntil := e.Timestamp.Add(rl.RetryAfter).UnixMilli()
+			msg = fmt.Sprintf("Way too many requests; IP(127.0.0.1) banned until %d.", bannedUntil)
+		}
+		msg += " Please use the websocket for live updates to avoid bans."
+		var retryAfter smocker.StringSlice
+		if m.Response != nil {
+			retryAfter = m.Response.Headers["Retry-After"]
+		}
+		setErrorResponse(m, rl.StatusCode, fmt.Sprintf(`{"code":-1003,"msg":%s}`, jsonString(msg)))
+		if retryAfter != nil {
+			m.Response.Headers["Retry-After"] = retryAfter
+		}
+	}
+	if m.Response != nil {
+		weight := strconv.FormatUint(uint64(n*binanceRequestWeight), 10)
+		m.Response.Headers["X-MBX-USED-WEIGHT"] = []string{weight}
+		m.Response.Headers["X-MBX-USED-WEIGHT-1M"] = []string{weight}
+	}
+	return nil
+}
+
+func (b Binance) wholeDayTicker(e ExchangeMock) string {
+	body := `{"symbol":"%s","lastPrice":"%.8f","bidPrice":"%.8f","askPrice":"%.8f","volume":"%.8f","closeTime":%d}`
+	return fmt.Sprintf(body, e.Symbol.Format("%s%s"), e.Price, e.Bid, e.Ask, e.Volume, e.Timestamp.UnixMilli())
+}
+
+// buildWholeDay builds the 24hr ticker of all symbols.
+func (b Binance) buildWholeDay(e []ExchangeMock) (*smocker.Mock, error) {
+	tickers := make([]string, 0, len(e))
+	for _, ex := range e {
+		tickers = append(tickers, b.wholeDayTicker(ex))
+	}
 	return &smocker.Mock{
 		Request: smocker.MockRequest{
 			Method: smocker.ShouldEqual("GET"),
 			Path:   smocker.ShouldEqual("/api/v3/ticker/24hr"),
 		},
 		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
+			Status: http.StatusOK,
 			Headers: map[string]smocker.StringSlice{
 				"Content-Type": []string{
 					"application/json",
 				},
 			},
-			Body: fmt.Sprintf(body, symbol, e.Price, e.Bid, e.Ask, e.Volume, e.Timestamp.UnixMilli()),
+			Body: fmt.Sprintf("[%s]", strings.Join(tickers, ",")),
 		},
 	}, nil
 }
@@ -66,3 +133,144 @@ func (b Binance) build(e ExchangeMock) (*smocker.Mock, error) {
 		},
 	}, nil
 }
+
+func (b Binance) buildDepth(e ExchangeMock) (*smocker.Mock, error) {
+	if e.OrderBook == nil {
+		return nil, nil
+	}
+	book := e.OrderBook.sorted()
+	body := `{"lastUpdateId":%d,"bids":%s,"asks":%s}`
+
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual("/api/v3/depth"),
+			QueryParams: map[string]smocker.StringMatcherSlice{
+				"symbol": []smocker.StringMatcher{
+					smocker.ShouldEqual(e.Symbol.Format("%s%s")),
+				},
+			},
+		},
+		Response: &smocker.MockResponse{
+			Status: e.StatusCode,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf(body,
+				e.Timestamp.UnixMilli(),
+				formatLevels(book.Bids, `["%.8f","%.8f"]`),
+				formatLevels(book.Asks, `["%.8f","%.8f"]`)),
+		},
+	}, nil
+}
+
+var binanceIntervals = []candleInterval{
+	{"1m", time.Minute},
+	{"3m", 3 * time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"2h", 2 * time.Hour},
+	{"4h", 4 * time.Hour},
+	{"6h", 6 * time.Hour},
+	{"8h", 8 * time.Hour},
+	{"12h", 12 * time.Hour},
+	{"1d", 24 * time.Hour},
+}
+
+// buildKlines builds klines for the interval, requests without a limit get
+// the last 500 klines.
+func (b Binance) buildKlines(interval candleInterval, limit int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		candles, ok := e.candles(interval.duration, limit, 500)
+		if !ok {
+			return nil, nil
+		}
+		kline := `[%d,"%.8f","%.8f","%.8f","%.8f","%.8f",%d,"%.8f",1,"0","0","0"]`
+		klines := make([]string, 0, len(candles))
+		for _, c := range candles {
+			klines = append(klines, fmt.Sprintf(kline,
+				c.Time.UnixMilli(),
+				c.Open,
+				c.High,
+				c.Low,
+				c.Close,
+				c.Volume,
+				c.Time.Add(interval.duration).UnixMilli()-1,
+				c.Volume.Mul(c.Close)))
+		}
+		query := map[string]smocker.StringMatcherSlice{
+			"symbol": []smocker.StringMatcher{
+				smocker.ShouldEqual(e.Symbol.Format("%s%s")),
+			},
+			"interval": []smocker.StringMatcher{
+				smocker.ShouldEqual(interval.name),
+			},
+			"limit": limitMatchers(limit, len(candles)),
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method:      smocker.ShouldEqual("GET"),
+				Path:        smocker.ShouldEqual("/api/v3/klines"),
+				QueryParams: query,
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf("[%s]", strings.Join(klines, ",")),
+			},
+		}, nil
+	}
+}
+
+// buildTrades builds recent trades, requests without a limit get the last 500.
+func (b Binance) buildTrades(limit int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		trades, ok := e.trades(limit, 500)
+		if !ok {
+			return nil, nil
+		}
+		trade := `{"id":%d,"price":"%.8f","qty":"%.8f","quoteQty":"%.8f","time":%d,"isBuyerMaker":%t,"isBestMatch":true}`
+		rows := make([]string, 0, len(trades))
+		for _, t := range trades {
+			rows = append(rows, fmt.Sprintf(trade,
+				t.ID,
+				t.Price,
+				t.Amount,
+				t.Amount.Mul(t.Price),
+				t.Time.UnixMilli(),
+				t.Side == TradeSell))
+		}
+		query := map[string]smocker.StringMatcherSlice{
+			"symbol": []smocker.StringMatcher{
+				smocker.ShouldEqual(e.Symbol.Format("%s%s")),
+			},
+			"limit": limitMatchers(limit, len(trades)),
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method:      smocker.ShouldEqual("GET"),
+				Path:        smocker.ShouldEqual("/api/v3/trades"),
+				QueryParams: query,
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf("[%s]", strings.Join(rows, ",")),
+			},
+		}, nil
+	}
+}
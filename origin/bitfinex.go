@@ -2,12 +2,14 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
 
-// NOTE: For symbols of 4 chars you have to write `SYMBOL:` otherwise API request to smocker will fail.
-// Example: AVAX/USD should be written in mock as `AVAX:/USD`
+// Bitfinex separates assets longer than 3 characters with a colon, e.g. AVAX/USD
+// is mocked as `tAVAX:USD` and BTC/USD as `tBTCUSD`.
 
 type Bitfinex struct{}
 
@@ -16,17 +18,25 @@ func (b Bitfinex) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	if err != nil {
 		return nil, err
 	}
-	other, err := CombineMocks(e, b.buildList)
+	list, err := AggregateMocks(e, b.buildList)
 	if err != nil {
 		return nil, err
 	}
-	return append(mocksOne, other...), nil
+	return append(mocksOne, auxiliary(list)...), nil
 }
 
-func (b Bitfinex) buildList(e ExchangeMock) (*smocker.Mock, error) {
-	symbol := e.Symbol.Format("t%s%s")
+func (b Bitfinex) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusInternalServerError, "10020", "symbol: invalid")
+	code, err := ee.numericCode()
+	if err != nil {
+		return err
+	}
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`["error",%d,%s]`, code, jsonString(ee.Message)))
+	return nil
+}
 
-	body := `[[
+func (b Bitfinex) listTicker(e ExchangeMock) string {
+	body := `[
 	"%s",
 	%f,
 	90.17754546000003,
@@ -38,26 +48,34 @@ func (b Bitfinex) buildList(e ExchangeMock) (*smocker.Mock, error) {
 	%f,
 	0.088377,
 	0.08629
-]]`
+]`
+	return fmt.Sprintf(body, b.symbol(e.Symbol), e.Bid, e.Ask, e.Price, e.Volume)
+}
 
+// buildList builds tickers of all symbols, requested with `symbols=ALL`.
+func (b Bitfinex) buildList(e []ExchangeMock) (*smocker.Mock, error) {
+	tickers := make([]string, 0, len(e))
+	for _, ex := range e {
+		tickers = append(tickers, b.listTicker(ex))
+	}
 	return &smocker.Mock{
 		Request: smocker.MockRequest{
 			Method: smocker.ShouldEqual("GET"),
-			Path:   smocker.ShouldEqual("/v2/tickers?symbols=ALL"),
+			Path:   smocker.ShouldEqual("/v2/tickers"),
 			QueryParams: map[string]smocker.StringMatcherSlice{
 				"symbols": []smocker.StringMatcher{
-					smocker.ShouldEqual(symbol),
+					smocker.ShouldEqual("ALL"),
 				},
 			},
 		},
 		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
+			Status: http.StatusOK,
 			Headers: map[string]smocker.StringSlice{
 				"Content-Type": []string{
 					"application/json",
 				},
 			},
-			Body: fmt.Sprintf(body, symbol, e.Bid, e.Ask, e.Price, e.Volume),
+			Body: fmt.Sprintf("[%s]", strings.Join(tickers, ",")),
 		},
 	}, nil
 }
@@ -79,7 +97,7 @@ func (b Bitfinex) build(e ExchangeMock) (*smocker.Mock, error) {
 	return &smocker.Mock{
 		Request: smocker.MockRequest{
 			Method: smocker.ShouldEqual("GET"),
-			Path:   smocker.ShouldEqual(fmt.Sprintf("/v2/ticker/t%s", e.Symbol.Format("%s%s"))),
+			Path:   smocker.ShouldEqual("/v2/ticker/" + b.symbol(e.Symbol)),
 		},
 		Response: &smocker.MockResponse{
 			Status: e.StatusCode,
@@ -92,3 +110,14 @@ func (b Bitfinex) build(e ExchangeMock) (*smocker.Mock, error) {
 		},
 	}, nil
 }
+
+// symbol returns the trading pair symbol. Colons in assets are ignored, so
+// symbols written as `AVAX:/USD` keep working.
+func (b Bitfinex) symbol(s Symbol) string {
+	base := strings.TrimSuffix(s.Base, ":")
+	quote := strings.TrimSuffix(s.Quote, ":")
+	if len(base) > 3 || len(quote) > 3 {
+		return fmt.Sprintf("t%s:%s", base, quote)
+	}
+	return fmt.Sprintf("t%s%s", base, quote)
+}
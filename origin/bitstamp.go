@@ -2,6 +2,7 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/chronicleprotocol/infestor/smocker"
@@ -10,7 +11,27 @@ import (
 type BitStamp struct{}
 
 func (b BitStamp) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
-	return CombineMocks(e, b.build)
+	mocks, err := CombineMocks(e, b.build)
+	if err != nil {
+		return nil, err
+	}
+	book, err := CombineMocks(e, b.buildOrderBook)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := buildLimitMocks(e, false, b.buildTransactions)
+	if err != nil {
+		return nil, err
+	}
+	mocks = append(mocks, book...)
+	return append(mocks, transactions...), nil
+}
+
+func (b BitStamp) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusNotFound, "API0005", "Invalid currency pair.")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"status":"error","reason":%s,"code":%s}`,
+		jsonString(ee.Message), jsonString(ee.Code)))
+	return nil
 }
 
 func (b BitStamp) build(e ExchangeMock) (*smocker.Mock, error) {
@@ -39,3 +60,71 @@ func (b BitStamp) build(e ExchangeMock) (*smocker.Mock, error) {
 		},
 	}, nil
 }
+
+func (b BitStamp) buildOrderBook(e ExchangeMock) (*smocker.Mock, error) {
+	if e.OrderBook == nil {
+		return nil, nil
+	}
+	symbol := strings.ToLower(e.Symbol.Format("%s%s"))
+	book := e.OrderBook.sorted()
+	body := `{"timestamp":"%d","microtimestamp":"%d","bids":%s,"asks":%s}`
+
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			// Clients often request the path with a trailing slash.
+			Path: smocker.ShouldMatch(fmt.Sprintf("^/api/v2/order_book/%s/?$", symbol)),
+		},
+		Response: &smocker.MockResponse{
+			Status: e.StatusCode,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf(body,
+				e.Timestamp.Unix(),
+				e.Timestamp.UnixMicro(),
+				formatLevels(book.Bids, `["%f","%f"]`),
+				formatLevels(book.Asks, `["%f","%f"]`)),
+		},
+	}, nil
+}
+
+// buildTransactions builds all trades, newest first, regardless of the requested time interval.
+func (b BitStamp) buildTransactions(_ int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		trades, ok := e.trades(0, 0)
+		if !ok {
+			return nil, nil
+		}
+		symbol := strings.ToLower(e.Symbol.Format("%s%s"))
+		transaction := `{"date":"%d","tid":"%d","amount":"%f","price":"%f","type":"%d"}`
+		rows := make([]string, 0, len(trades))
+		for i := len(trades) - 1; i >= 0; i-- {
+			t := trades[i]
+			// Type is 0 for buys and 1 for sells.
+			typ := 0
+			if t.Side == TradeSell {
+				typ = 1
+			}
+			rows = append(rows, fmt.Sprintf(transaction, t.Time.Unix(), t.ID, t.Amount, t.Price, typ))
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method: smocker.ShouldEqual("GET"),
+				Path:   smocker.ShouldMatch(fmt.Sprintf("^/api/v2/transactions/%s/?$", symbol)),
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf("[%s]", strings.Join(rows, ",")),
+			},
+		}, nil
+	}
+}
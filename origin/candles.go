@@ -0,0 +1,146 @@
+package origin
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// Candle is an OHLCV bar of a price series, Time is the open time.
+type Candle struct {
+	Time   time.Time
+	Open   Decimal
+	High   Decimal
+	Low    Decimal
+	Close  Decimal
+	Volume Decimal
+}
+
+// NewCandle returns the candle opened at t.
+func NewCandle(t time.Time, open, high, low, close, volume float64) Candle {
+	return Candle{
+		Time:   t,
+		Open:   NewDecimal(open),
+		High:   NewDecimal(high),
+		Low:    NewDecimal(low),
+		Close:  NewDecimal(close),
+		Volume: NewDecimal(volume),
+	}
+}
+
+// CandleSeries is a price series served by candle endpoints of origins, see
+// ExchangeMock.WithCandles.
+type CandleSeries struct {
+	// Interval of candles. Origins serve multiples of the interval up to a day,
+	// candles are combined as needed.
+	Interval time.Duration
+	Candles  []Candle
+}
+
+// WithCandles sets the price series served by candle endpoints of the exchange.
+// Candle endpoints are mocked only for exchange mocks with candles.
+func (e *ExchangeMock) WithCandles(interval time.Duration, candles ...Candle) *ExchangeMock {
+	e.Candles = &CandleSeries{Interval: interval, Candles: candles}
+	return e
+}
+
+// resample returns candles of the series for the interval in ascending order,
+// false if the interval is not a multiple of the series interval.
+func (s CandleSeries) resample(interval time.Duration) ([]Candle, bool) {
+	if s.Interval <= 0 || interval < s.Interval || interval%s.Interval != 0 {
+		return nil, false
+	}
+	candles := append([]Candle{}, s.Candles...)
+	sort.SliceStable(candles, func(i, j int) bool { return candles[i].Time.Before(candles[j].Time) })
+	var res []Candle
+	for _, c := range candles {
+		t := c.Time.Truncate(interval)
+		if len(res) == 0 || !res[len(res)-1].Time.Equal(t) {
+			c.Time = t
+			res = append(res, c)
+			continue
+		}
+		last := &res[len(res)-1]
+		if c.High.Rat().Cmp(last.High.Rat()) > 0 {
+			last.High = c.High
+		}
+		if c.Low.Rat().Cmp(last.Low.Rat()) < 0 {
+			last.Low = c.Low
+		}
+		last.Close = c.Close
+		last.Volume = Decimal{r: new(big.Rat).Add(last.Volume.Rat(), c.Volume.Rat()), decimals: last.Volume.decimals}
+	}
+	return res, true
+}
+
+// withDecimals returns a copy of the series with values formatted with n decimals.
+func (s CandleSeries) withDecimals(n int) CandleSeries {
+	res := CandleSeries{Interval: s.Interval, Candles: make([]Candle, len(s.Candles))}
+	for i, c := range s.Candles {
+		res.Candles[i] = Candle{
+			Time:   c.Time,
+			Open:   c.Open.WithDecimals(n),
+			High:   c.High.WithDecimals(n),
+			Low:    c.Low.WithDecimals(n),
+			Close:  c.Close.WithDecimals(n),
+			Volume: c.Volume.WithDecimals(n),
+		}
+	}
+	return res
+}
+
+// candles returns candles of the exchange mock for the interval and the limit,
+// false if there are no candles to serve. See limitItems.
+func (e ExchangeMock) candles(interval time.Duration, limit, def int) ([]Candle, bool) {
+	if e.Candles == nil {
+		return nil, false
+	}
+	candles, ok := e.Candles.resample(interval)
+	if !ok || len(candles) == 0 {
+		return nil, false
+	}
+	n, ok := limitItems(len(candles), limit, def)
+	if !ok {
+		return nil, false
+	}
+	return candles[len(candles)-n:], true
+}
+
+// candleInterval is a candle interval of an origin, named as in its API.
+type candleInterval struct {
+	name     string
+	duration time.Duration
+}
+
+// buildCandleMocks builds mocks of candles with f for each interval and limit,
+// see buildLimitMocks. Mocks of intervals after the first one served for an
+// exchange mock, usually the series interval, are auxiliary.
+func buildCandleMocks(
+	e []ExchangeMock,
+	intervals []candleInterval,
+	limits bool,
+	f func(interval candleInterval, limit int) MockableFunc,
+) ([]*smocker.Mock, error) {
+	var mocks []*smocker.Mock
+	for _, ex := range e {
+		served := false
+		for _, interval := range intervals {
+			interval := interval
+			m, err := buildLimitMocks([]ExchangeMock{ex}, limits, func(limit int) MockableFunc { return f(interval, limit) })
+			if err != nil {
+				return nil, err
+			}
+			if len(m) == 0 {
+				continue
+			}
+			if served {
+				auxiliary(m)
+			}
+			served = true
+			mocks = append(mocks, m...)
+		}
+	}
+	return mocks, nil
+}
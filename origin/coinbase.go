@@ -2,6 +2,9 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -9,7 +12,38 @@ import (
 type Coinbase struct{}
 
 func (c Coinbase) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
-	return CombineMocks(e, c.build)
+	mocks, err := CombineMocks(e, c.build)
+	if err != nil {
+		return nil, err
+	}
+	book, err := CombineMocks(e, c.buildBook)
+	if err != nil {
+		return nil, err
+	}
+	// Mocks for level 1 are added after full books, so they take precedence.
+	bookLevel1, err := CombineMocks(e, c.buildBookLevel1)
+	if err != nil {
+		return nil, err
+	}
+	candles, err := buildCandleMocks(e, coinbaseGranularities, false, c.buildCandles)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := buildLimitMocks(e, false, c.buildTrades)
+	if err != nil {
+		return nil, err
+	}
+	mocks = append(mocks, book...)
+	mocks = append(mocks, auxiliary(bookLevel1)...)
+	mocks = append(mocks, candles...)
+	return append(mocks, trades...), nil
+}
+
+func (c Coinbase) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	// Coinbase errors have no code.
+	ee := e.Error.withDefaults(http.StatusNotFound, "", "NotFound")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"message":%s}`, jsonString(ee.Message)))
+	return nil
 }
 
 func (c Coinbase) build(e ExchangeMock) (*smocker.Mock, error) {
@@ -39,3 +73,136 @@ func (c Coinbase) build(e ExchangeMock) (*smocker.Mock, error) {
 		},
 	}, nil
 }
+
+// buildBook builds the level 2 order book, which is also served for other levels.
+func (c Coinbase) buildBook(e ExchangeMock) (*smocker.Mock, error) {
+	if e.OrderBook == nil {
+		return nil, nil
+	}
+	return c.book(e, e.OrderBook.sorted(), nil)
+}
+
+// buildBookLevel1 builds the order book with the best bid and ask only.
+func (c Coinbase) buildBookLevel1(e ExchangeMock) (*smocker.Mock, error) {
+	if e.OrderBook == nil {
+		return nil, nil
+	}
+	return c.book(e, e.OrderBook.sorted().limit(1), map[string]smocker.StringMatcherSlice{
+		"level": []smocker.StringMatcher{
+			smocker.ShouldEqual("1"),
+		},
+	})
+}
+
+func (c Coinbase) book(e ExchangeMock, book OrderBook, query smocker.MultiMapMatcher) (*smocker.Mock, error) {
+	body := `{"bids":%s,"asks":%s,"sequence":%d,"auction_mode":false,"auction":null}`
+
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method:      smocker.ShouldEqual("GET"),
+			Path:        smocker.ShouldEqual(fmt.Sprintf("/products/%s/book", e.Symbol.Format("%s-%s"))),
+			QueryParams: query,
+		},
+		Response: &smocker.MockResponse{
+			Status: e.StatusCode,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf(body,
+				formatLevels(book.Bids, `["%f","%f",1]`),
+				formatLevels(book.Asks, `["%f","%f",1]`),
+				e.Timestamp.UnixMilli()),
+		},
+	}, nil
+}
+
+// coinbaseGranularities are in seconds.
+var coinbaseGranularities = []candleInterval{
+	{"60", time.Minute},
+	{"300", 5 * time.Minute},
+	{"900", 15 * time.Minute},
+	{"3600", time.Hour},
+	{"21600", 6 * time.Hour},
+	{"86400", 24 * time.Hour},
+}
+
+// buildCandles builds candles for the granularity, newest first.
+func (c Coinbase) buildCandles(interval candleInterval, _ int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		candles, ok := e.candles(interval.duration, 0, 300)
+		if !ok {
+			return nil, nil
+		}
+		row := `[%d,%f,%f,%f,%f,%f]`
+		rows := make([]string, 0, len(candles))
+		for i := len(candles) - 1; i >= 0; i-- {
+			c := candles[i]
+			rows = append(rows, fmt.Sprintf(row, c.Time.Unix(), c.Low, c.High, c.Open, c.Close, c.Volume))
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method: smocker.ShouldEqual("GET"),
+				Path:   smocker.ShouldEqual(fmt.Sprintf("/products/%s/candles", e.Symbol.Format("%s-%s"))),
+				QueryParams: map[string]smocker.StringMatcherSlice{
+					"granularity": []smocker.StringMatcher{
+						smocker.ShouldEqual(interval.name),
+					},
+				},
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf("[%s]", strings.Join(rows, ",")),
+			},
+		}, nil
+	}
+}
+
+// buildTrades builds the last 1000 trades, newest first.
+func (c Coinbase) buildTrades(_ int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		trades, ok := e.trades(0, 1000)
+		if !ok {
+			return nil, nil
+		}
+		trade := `{"time":%s,"trade_id":%d,"price":"%.8f","size":"%.8f","side":%s}`
+		rows := make([]string, 0, len(trades))
+		for i := len(trades) - 1; i >= 0; i-- {
+			t := trades[i]
+			// Coinbase reports the side of the maker order.
+			side := TradeBuy
+			if t.Side == TradeBuy {
+				side = TradeSell
+			}
+			rows = append(rows, fmt.Sprintf(trade,
+				jsonString(t.Time.UTC().Format(time.RFC3339Nano)),
+				t.ID,
+				t.Price,
+				t.Amount,
+				jsonString(string(side))))
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method: smocker.ShouldEqual("GET"),
+				Path:   smocker.ShouldEqual(fmt.Sprintf("/products/%s/trades", e.Symbol.Format("%s-%s"))),
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf("[%s]", strings.Join(rows, ",")),
+			},
+		}, nil
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"math/big"
 
 	"github.com/chronicleprotocol/infestor/smocker"
-	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/abi"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -47,142 +47,140 @@ func (b Curve) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 }
 
 func (b Curve) buildCoins(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
+	}
+	funcData, err := e.functionData("coins")
+	if err != nil {
+		return nil, err
 	}
-	funcData, ok := e.Custom["coins"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
+	if len(funcData) < 1 {
 		return nil, fmt.Errorf("not found function data for coins")
 	}
 
 	var calls []MultiCall
 	var data []any
-	for _, funcDataItem := range funcData {
-		coinsArg, _ := coins.EncodeArgs(funcDataItem.Args[0].(int))
+	for i, funcDataItem := range funcData {
+		index, err := argAt[int](funcDataItem, 0, "index")
+		if err != nil {
+			return nil, functionDataError("coins", i, err)
+		}
+		coin, err := returnAt[types.Address](funcDataItem, 0, "coin")
+		if err != nil {
+			return nil, functionDataError("coins", i, err)
+		}
+		coinsArg, err := coins.EncodeArgs(index)
+		if err != nil {
+			return nil, functionDataError("coins", i, err)
+		}
 
 		calls = append(calls, MultiCall{
 			Target: funcDataItem.Address,
 			Data:   coinsArg,
 		})
-		data = append(data, types.Bytes(funcDataItem.Return[0].(types.Address).Bytes()).PadLeft(32))
-	}
-
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+		data = append(data, types.Bytes(coin.Bytes()).PadLeft(32))
+	}
+	return multiCallMock(e, blockNumber, calls, data)
 }
 
 func (b Curve) buildGetDy1(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int) // Should use same block number with EthRPC exchange
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+	return b.buildGetDy(e, "get_dy1", getDy1)
+}
+
+func (b Curve) buildGetDy2(e ExchangeMock) (*smocker.Mock, error) {
+	return b.buildGetDy(e, "get_dy2", getDy2)
+}
+
+func (b Curve) buildGetDy(e ExchangeMock, funcName string, method *abi.Method) (*smocker.Mock, error) {
+	blockNumber, err := e.blockNumber() // Should use same block number with EthRPC exchange
+	if err != nil {
+		return nil, err
+	}
+	funcData, err := e.functionData(funcName)
+	if err != nil {
+		return nil, err
 	}
-	funcData, ok := e.Custom["get_dy1"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
+	if len(funcData) < 1 {
 		return nil, nil
 	}
 
 	var calls []MultiCall
 	var data []any
 	for i := 0; i < len(funcData); i++ {
-		getDy1Data, _ := getDy1.EncodeArgs(
-			funcData[i].Args[0].(int), funcData[i].Args[1].(int), funcData[i].Args[2].(*big.Int))
+		getDyData, price, err := encodeGetDy(funcData[i], method)
+		if err != nil {
+			return nil, functionDataError(funcName, i, err)
+		}
 		calls = append(calls, MultiCall{
 			Target: funcData[i].Address,
-			Data:   getDy1Data,
+			Data:   getDyData,
 		})
-		price := funcData[i].Return[0].(*big.Int)
 		data = append(data, types.Bytes(price.Bytes()).PadLeft(32))
 	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+	return multiCallMock(e, blockNumber, calls, data)
 }
 
-func (b Curve) buildGetDy2(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int) // Should use same block number with EthRPC exchange
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+func encodeGetDy(f FunctionData, method *abi.Method) ([]byte, *big.Int, error) {
+	i, err := argAt[int](f, 0, "i")
+	if err != nil {
+		return nil, nil, err
 	}
-	funcData, ok := e.Custom["get_dy2"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
-		return nil, nil
+	j, err := argAt[int](f, 1, "j")
+	if err != nil {
+		return nil, nil, err
 	}
+	dx, err := argAt[*big.Int](f, 2, "dx")
+	if err != nil {
+		return nil, nil, err
+	}
+	dy, err := returnAt[*big.Int](f, 0, "dy")
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := method.EncodeArgs(i, j, dx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return args, dy, nil
+}
 
-	var calls []MultiCall
-	var data []any
-	for i := 0; i < len(funcData); i++ {
-		getDy2Data, _ := getDy2.EncodeArgs(
-			funcData[i].Args[0].(int), funcData[i].Args[1].(int), funcData[i].Args[2].(*big.Int))
-		calls = append(calls, MultiCall{
-			Target: funcData[i].Address,
-			Data:   getDy2Data,
+// CurvePool is a Curve pool, its coins and quotes for `get_dy`.
+type CurvePool struct {
+	Address types.Address
+	Coins   []types.Address
+	Quotes  []CurveQuote
+	// CryptoSwap pools take uint256 coin indexes in `get_dy`, stableswap pools take int128.
+	CryptoSwap bool
+}
+
+// CurveQuote is the amount Dy of coin J received for the amount Dx of coin I.
+type CurveQuote struct {
+	I  int
+	J  int
+	Dx *big.Int
+	Dy *big.Int
+}
+
+func (p CurvePool) FunctionData() map[string][]FunctionData {
+	getDy := "get_dy1"
+	if p.CryptoSwap {
+		getDy = "get_dy2"
+	}
+	res := map[string][]FunctionData{}
+	for i, coin := range p.Coins {
+		res["coins"] = append(res["coins"], FunctionData{
+			Address: p.Address,
+			Args:    []any{i},
+			Return:  []any{coin},
+		})
+	}
+	for _, q := range p.Quotes {
+		res[getDy] = append(res[getDy], FunctionData{
+			Address: p.Address,
+			Args:    []any{q.I, q.J, q.Dx},
+			Return:  []any{q.Dy},
 		})
-		price := funcData[i].Return[0].(*big.Int)
-		data = append(data, types.Bytes(price.Bytes()).PadLeft(32))
 	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+	return res
 }
@@ -0,0 +1,212 @@
+package origin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxExactDecimals limits the number of decimals of values that have no finite
+// decimal representation, e.g. results of division.
+const maxExactDecimals = 36
+
+// Decimal is an arbitrary-precision decimal number used for prices and volumes.
+// The zero value is 0.
+//
+// Decimal implements fmt.Formatter, so origins render it with the usual verbs:
+// `%f` (6 decimals, as for float64), `%.8f` (8 decimals), `%v` and `%s` (all
+// significant decimals). ExchangeMock.WithDecimals overrides the number of
+// decimals for all verbs.
+type Decimal struct {
+	r *big.Rat
+	// decimals overrides the precision of formatting verbs, set from ExchangeMock.Decimals.
+	decimals *int
+	// text overrides the formatted value, used to inject invalid prices.
+	text string
+}
+
+// NewDecimal returns the decimal with the shortest representation of the float,
+// e.g. 0.1 is exactly 0.1.
+func NewDecimal(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		// NaN and infinities have no decimal representation.
+		return Decimal{}
+	}
+	return d
+}
+
+// NewDecimalFromBigFloat returns the decimal with the shortest representation
+// of the float at its precision.
+func NewDecimalFromBigFloat(f *big.Float) Decimal {
+	if f == nil || f.IsInf() {
+		return Decimal{}
+	}
+	d, _ := ParseDecimal(f.Text('f', -1))
+	return d
+}
+
+// ParseDecimal parses a decimal number, e.g. `0.000000012345` or `1.5e-8`.
+func ParseDecimal(s string) (Decimal, error) {
+	if strings.Contains(s, "/") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r: r}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid numbers.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Rat returns a copy of the value as big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(d.rat())
+}
+
+// Float64 returns the nearest float64 value.
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// IsZero reports whether the value is 0.
+func (d Decimal) IsZero() bool {
+	return d.rat().Sign() == 0
+}
+
+// Mul returns the product of d and x. The text override of d or x is kept, so
+// values computed from fault-marked prices, e.g. quote volumes, are marked too.
+func (d Decimal) Mul(x Decimal) Decimal {
+	text := d.text
+	if text == "" {
+		text = x.text
+	}
+	return Decimal{r: new(big.Rat).Mul(d.rat(), x.rat()), decimals: d.decimals, text: text}
+}
+
+// WithDecimals returns the value that is always formatted with n decimals,
+// negative n formats all significant decimals.
+func (d Decimal) WithDecimals(n int) Decimal {
+	d.decimals = &n
+	return d
+}
+
+// Text returns the value with n decimals, rounded half away from zero.
+// Negative n returns all significant decimals.
+func (d Decimal) Text(n int) string {
+	if n < 0 {
+		n = exactDecimals(d.rat())
+	}
+	return d.rat().FloatString(n)
+}
+
+// String returns the value with all significant decimals.
+func (d Decimal) String() string {
+	if d.text != "" {
+		return d.text
+	}
+	if d.decimals != nil {
+		return d.Text(*d.decimals)
+	}
+	return d.Text(-1)
+}
+
+func (d Decimal) Format(s fmt.State, verb rune) {
+	prec, hasPrec := s.Precision()
+	switch {
+	case d.text != "":
+		_, _ = io.WriteString(s, d.text)
+		return
+	case verb != 'f' && verb != 'F' && verb != 'v' && verb != 's':
+		fmt.Fprintf(s, fmt.FormatString(s, verb), d.Float64())
+		return
+	case d.decimals != nil:
+		prec = *d.decimals
+	case !hasPrec && (verb == 'f' || verb == 'F'):
+		prec = 6 // Same as float64.
+	case !hasPrec:
+		prec = -1
+	}
+	str := d.Text(prec)
+	if s.Flag('+') && d.rat().Sign() >= 0 {
+		str = "+" + str
+	}
+	if w, ok := s.Width(); ok && len(str) < w {
+		pad := strings.Repeat(" ", w-len(str))
+		if s.Flag('-') {
+			str += pad
+		} else {
+			str = pad + str
+		}
+	}
+	_, _ = io.WriteString(s, str)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// UnmarshalJSON accepts numbers and strings, numbers are read exactly as written.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return d.UnmarshalText([]byte(s))
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid decimal %s", data)
+	}
+	return d.UnmarshalText([]byte(n))
+}
+
+// exactDecimals returns the number of decimals needed to represent r exactly.
+func exactDecimals(r *big.Rat) int {
+	denom := new(big.Int).Set(r.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+	var twos, fives int
+	mod := new(big.Int)
+	for denom.Cmp(big.NewInt(1)) > 0 {
+		switch {
+		case mod.Mod(denom, two).Sign() == 0:
+			denom.Quo(denom, two)
+			twos++
+		case mod.Mod(denom, five).Sign() == 0:
+			denom.Quo(denom, five)
+			fives++
+		default:
+			return maxExactDecimals
+		}
+	}
+	if fives > twos {
+		return fives
+	}
+	return twos
+}
@@ -0,0 +1,45 @@
+package origin
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimal(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		out string
+	}{
+		{"1", "1"},
+		{"-0.5", "-0.5"},
+		{"1.5e-8", "0.000000015"},
+		{"123456789.123456789123456789", "123456789.123456789123456789"},
+	} {
+		d, err := ParseDecimal(tt.in)
+		require.NoError(t, err)
+		require.Equal(t, tt.out, d.String())
+		require.Equal(t, tt.out, fmt.Sprintf("%v", d))
+	}
+
+	d := MustParseDecimal("2.5")
+	require.Equal(t, "2.500000", fmt.Sprintf("%f", d))
+	require.Equal(t, "2.50", fmt.Sprintf("%.2f", d))
+	require.Equal(t, "3", fmt.Sprintf("%.0f", d))
+	require.Equal(t, "2.5000", fmt.Sprintf("%.2f", d.WithDecimals(4)))
+	require.Equal(t, "0.1", NewDecimal(0.1).String())
+	require.Equal(t, "0.25", d.Mul(NewDecimal(0.1)).String())
+	require.True(t, Decimal{}.IsZero())
+
+	// Values computed from marked values are marked too.
+	nan := Decimal{r: big.NewRat(2, 1), text: "NaN"}
+	require.Equal(t, "NaN", nan.Mul(d).String())
+	require.Equal(t, "NaN", fmt.Sprintf("%.8f", d.Mul(nan)))
+
+	for _, in := range []string{"", "abc", "1/3", "NaN"} {
+		_, err := ParseDecimal(in)
+		require.Error(t, err, in)
+	}
+}
@@ -1,11 +1,9 @@
 package origin
 
 import (
-	"fmt"
 	"math/big"
 
 	"github.com/chronicleprotocol/infestor/smocker"
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -32,47 +30,19 @@ func (b DSR) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 }
 
 func (b DSR) buildDSR(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
-	}
-	funcData, ok := e.Custom["dsr"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
-		return nil, fmt.Errorf("not found function data for dsr")
-	}
-
-	var calls []MultiCall
-	var data []any
-	for i := 0; i < len(funcData); i++ {
-		dsrData, _ := dsr.EncodeArgs()
-		calls = append(calls, MultiCall{
-			Target: funcData[i].Address,
-			Data:   dsrData,
-		})
-		rate := funcData[i].Return[0].(*big.Int)
-		data = append(data, types.Bytes(rate.Bytes()).PadLeft(32))
-	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
+	return buildUint256Calls(e, "dsr", func(FunctionData) ([]byte, error) {
+		return dsr.EncodeArgs()
+	})
+}
 
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
+// DSRPot is the Maker pot contract and its Dai Savings Rate.
+type DSRPot struct {
+	Address types.Address
+	Rate    *big.Int
+}
 
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+func (p DSRPot) FunctionData() map[string][]FunctionData {
+	return map[string][]FunctionData{
+		"dsr": {{Address: p.Address, Args: []any{}, Return: []any{p.Rate}}},
+	}
 }
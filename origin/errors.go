@@ -0,0 +1,103 @@
+package origin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// ExchangeError is an error response of an origin, see ExchangeMock.WithError.
+// Zero fields are replaced with the origin's typical "unknown symbol" error,
+// including the status code, which is 200 for exchanges that report errors in
+// the body only (e.g. Kraken, Huobi and JSON-RPC nodes).
+type ExchangeError struct {
+	StatusCode int
+	// Code is the exchange error code. Exchanges with numeric codes (e.g. Binance)
+	// require a number.
+	Code    string
+	Message string
+}
+
+// ErrorMockable is implemented by origins that respond with their own error payloads.
+type ErrorMockable interface {
+	// BuildErrorResponse replaces the response of the mock built for the exchange
+	// mock with the error set by ExchangeMock.WithError.
+	BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error
+}
+
+// WithError makes the exchange respond with the error payload of the origin,
+// the status code set by WithStatusCode is ignored.
+func (e *ExchangeMock) WithError(err ExchangeError) *ExchangeMock {
+	e.Error = &err
+	return e
+}
+
+// withDefaults returns the error with zero fields set to given values.
+func (ee ExchangeError) withDefaults(statusCode int, code, message string) ExchangeError {
+	if ee.StatusCode == 0 {
+		ee.StatusCode = statusCode
+	}
+	if ee.Code == "" {
+		ee.Code = code
+	}
+	if ee.Message == "" {
+		ee.Message = message
+	}
+	return ee
+}
+
+// numericCode returns the error code as a number.
+func (ee ExchangeError) numericCode() (int64, error) {
+	code, err := strconv.ParseInt(ee.Code, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error code %q must be a number", ee.Code)
+	}
+	return code, nil
+}
+
+// applyError sets the error response of the mock built for the exchange mock.
+// Without an error set, bodies of error status codes are removed.
+func applyError(e ExchangeMock, m *smocker.Mock) error {
+	if e.Error == nil {
+		if m.Response != nil && m.Response.Status >= http.StatusBadRequest {
+			m.Response.Body = ""
+		}
+		return nil
+	}
+	ex, ok := lookupExchange(e.Name)
+	if !ok {
+		return fmt.Errorf("failed to find exchange name %s", e.Name)
+	}
+	em, ok := ex.(ErrorMockable)
+	if !ok {
+		return fmt.Errorf("exchange %s does not support error responses", e.Name)
+	}
+	if err := em.BuildErrorResponse(e, m); err != nil {
+		return fmt.Errorf("failed to build error response: %w", err)
+	}
+	return nil
+}
+
+// setErrorResponse replaces the response of the mock with the JSON error body.
+func setErrorResponse(m *smocker.Mock, statusCode int, body string) {
+	m.DynamicResponse = nil
+	m.Proxy = nil
+	m.Response = &smocker.MockResponse{
+		Status: statusCode,
+		Headers: map[string]smocker.StringSlice{
+			"Content-Type": []string{
+				"application/json",
+			},
+		},
+		Body: body,
+	}
+}
+
+// jsonString returns s as a JSON string literal.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -5,11 +5,12 @@ package origin
 import (
 	"fmt"
 	"math/big"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 	"github.com/defiweb/go-eth/abi"
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -19,10 +20,45 @@ const RPCJSONResult = `{
   "result": "%s"
 }`
 
+// RPCJSONResultScript is a go_template_json script rendering a JSON-RPC response that echoes the id
+// of the request. It has to be formatted with the HTTP status and the JSON encoded result, which may
+// use template actions to compute the result from the request, e.g. `{{ .Request.Body.params | toJson }}`.
+const RPCJSONResultScript = `{
+  "status": %d,
+  "headers": {"Content-Type": ["application/json"]},
+  "body": {"jsonrpc": "2.0", "id": {{ .Request.Body.id | toJson }}, "result": %s}
+}`
+
+// RPCJSONErrorScript is like RPCJSONResultScript, but renders a JSON-RPC error. It has to be
+// formatted with the HTTP status, the error code and the JSON encoded message.
+const RPCJSONErrorScript = `{
+  "status": %d,
+  "headers": {"Content-Type": ["application/json"]},
+  "body": {"jsonrpc": "2.0", "id": {{ .Request.Body.id | toJson }}, "error": {"code": %d, "message": %s}}
+}`
+
 const RPCCallRequestJSON = `{"method":"eth_call","params":[{"from":"%s","to":"%s","data":"%s"},"%s"],"id":1,"jsonrpc":"2.0"}` //nolint:lll
 
 type EthRPC struct{}
 
+// RPCDynamicResponse returns a JSON-RPC response that echoes the id of the request. The result
+// is a JSON value that may use template actions, see RPCJSONResultScript. As with static responses,
+// the body is empty for error status codes.
+func RPCDynamicResponse(statusCode int, result string) *smocker.DynamicMockResponse {
+	script := fmt.Sprintf(RPCJSONResultScript, statusCode, result)
+	if statusCode >= http.StatusBadRequest {
+		script = fmt.Sprintf(`{"status": %d}`, statusCode)
+	}
+	return &smocker.DynamicMockResponse{
+		Engine: smocker.GoTemplateJSONEngine,
+		Script: script,
+	}
+}
+
+func rpcResult(statusCode int, result string) *smocker.DynamicMockResponse {
+	return RPCDynamicResponse(statusCode, strconv.Quote(result))
+}
+
 func (b EthRPC) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	mocks := make([]*smocker.Mock, 0)
 	m, err := CombineMocks(e, b.buildChainID)
@@ -52,6 +88,23 @@ func (b EthRPC) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	return mocks, nil
 }
 
+func (b EthRPC) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusOK, "-32000", "execution reverted")
+	code, err := ee.numericCode()
+	if err != nil {
+		return err
+	}
+	// Escape template actions in the message.
+	message := strings.ReplaceAll(jsonString(ee.Message), "{{", `{{"{{"}}`)
+	m.Response = nil
+	m.Proxy = nil
+	m.DynamicResponse = &smocker.DynamicMockResponse{
+		Engine: smocker.GoTemplateJSONEngine,
+		Script: fmt.Sprintf(RPCJSONErrorScript, ee.StatusCode, code, message),
+	}
+	return nil
+}
+
 func (b EthRPC) buildChainID(e ExchangeMock) (*smocker.Mock, error) {
 	m := smocker.ShouldContainSubstring("eth_chainId")
 
@@ -63,25 +116,17 @@ func (b EthRPC) buildChainID(e ExchangeMock) (*smocker.Mock, error) {
 				BodyString: &m,
 			},
 		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: `{"jsonrpc":"2.0","id":1,"result":"1"}`,
-		},
+		DynamicResponse: rpcResult(e.StatusCode, "1"),
 	}, nil
 }
 
 func (b EthRPC) buildBlockNumber(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
 	}
 
-	blockNumberHex := strconv.FormatInt(int64(blockNumber), 16)
+	blockNumberHex := strconv.FormatInt(blockNumber, 16)
 
 	m := smocker.ShouldContainSubstring("eth_blockNumber")
 
@@ -93,15 +138,7 @@ func (b EthRPC) buildBlockNumber(e ExchangeMock) (*smocker.Mock, error) {
 				BodyString: &m,
 			},
 		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":"%s"}`, blockNumberHex),
-		},
+		DynamicResponse: rpcResult(e.StatusCode, blockNumberHex),
 	}, nil
 }
 
@@ -116,29 +153,27 @@ func (b EthRPC) buildNetVersion(e ExchangeMock) (*smocker.Mock, error) {
 				BodyString: &m,
 			},
 		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: `{"jsonrpc":"2.0","id":1,"result":"1"}`,
-		},
+		DynamicResponse: rpcResult(e.StatusCode, "1"),
 	}, nil
 }
 
 func (b EthRPC) buildSymbols(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, ok := e.Custom["blockNumber"].(int) // Should use same block number with EthRPC exchange
-	if !ok {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber() // Should use same block number with EthRPC exchange
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := e.functionData("symbols")
+	if err != nil {
+		return nil, err
 	}
-	symbols, err := e.Custom["symbols"].([]FunctionData)
-	if !err {
+	if symbols == nil {
 		return nil, nil
 	}
-	decimals, err := e.Custom["decimals"].([]FunctionData)
-	if !err {
+	decimals, err := e.functionData("decimals")
+	if err != nil {
+		return nil, err
+	}
+	if decimals == nil {
 		return nil, fmt.Errorf("not found return values for decimals")
 	}
 	if len(symbols) != len(decimals) {
@@ -161,36 +196,46 @@ func (b EthRPC) buildSymbols(e ExchangeMock) (*smocker.Mock, error) {
 
 	var data []any
 	for i := 0; i < len(symbols); i++ {
-		symbol := symbols[i].Return[0].(string)
+		symbol, err := returnAt[string](symbols[i], 0, "symbol")
+		if err != nil {
+			return nil, functionDataError("symbols", i, err)
+		}
+		decimal, err := returnAt[*big.Int](decimals[i], 0, "decimals")
+		if err != nil {
+			return nil, functionDataError("decimals", i, err)
+		}
 		symbolAbi := abi.MustParseType("(string memory)")
 		symbolMap := make(map[string]string)
 		symbolMap["arg0"] = symbol
-		decimal := decimals[i].Return[0].(*big.Int)
+		symbolBytes, err := abi.EncodeValue(symbolAbi, symbolMap)
+		if err != nil {
+			return nil, functionDataError("symbols", i, err)
+		}
 		decimalBytes := types.Bytes(decimal.Bytes()).PadLeft(32)
 
-		data = append(data, abi.MustEncodeValue(symbolAbi, symbolMap), decimalBytes)
+		data = append(data, symbolBytes, decimalBytes)
 	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
+	return multiCallMock(e, blockNumber, calls, data)
+}
 
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
+// ERC20Token is a token queried by on-chain origins for its symbol and decimals.
+type ERC20Token struct {
+	Address  types.Address
+	Symbol   string
+	Decimals uint8
+}
 
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+func (t ERC20Token) FunctionData() map[string][]FunctionData {
+	return map[string][]FunctionData{
+		"symbols": {{
+			Address: t.Address,
+			Args:    []any{},
+			Return:  []any{t.Symbol},
+		}},
+		"decimals": {{
+			Address: t.Address,
+			Args:    []any{},
+			Return:  []any{big.NewInt(int64(t.Decimals))},
+		}},
+	}
 }
@@ -3,7 +3,9 @@ package origin
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chronicleprotocol/infestor/smocker"
@@ -16,23 +18,169 @@ type Mockable interface {
 	BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error)
 }
 
+// MockableFunc builds a mock for a single exchange mock. It implements Mockable,
+// so simple origins may be registered without declaring a type.
 type MockableFunc func(e ExchangeMock) (*smocker.Mock, error)
 
+func (f MockableFunc) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
+	return CombineMocks(e, f)
+}
+
+// Labels set on built mocks, see smocker.Mock.Labels.
+const (
+	// LabelOrigin is the name of the origin the mock was built for.
+	LabelOrigin = "origin"
+	// LabelSymbols are the comma separated symbols of exchange mocks the mock was built for.
+	LabelSymbols = "symbols"
+	// LabelAuxiliary is set to "true" on mocks that clients usually don't call
+	// all of: endpoints mocked for every exchange mock, e.g. lists of assets or
+	// all-tickers endpoints, and variants of endpoints, e.g. other candle
+	// intervals, limits, order book depths, or later calls of sequences.
+	LabelAuxiliary = "auxiliary"
+)
+
+// labelMocks labels mocks as built for the exchange mocks, other labels are kept.
+func labelMocks(mocks []*smocker.Mock, e ...ExchangeMock) {
+	symbols := make([]string, 0, len(e))
+	for _, ex := range e {
+		symbols = append(symbols, ex.Symbol.String())
+	}
+	for _, m := range mocks {
+		if m.Labels == nil {
+			m.Labels = make(map[string]string)
+		}
+		m.Labels[LabelOrigin] = e[0].Name
+		m.Labels[LabelSymbols] = strings.Join(symbols, ",")
+	}
+}
+
+// auxiliary labels mocks as auxiliary, see LabelAuxiliary.
+func auxiliary(mocks []*smocker.Mock) []*smocker.Mock {
+	for _, m := range mocks {
+		if m.Labels == nil {
+			m.Labels = make(map[string]string)
+		}
+		m.Labels[LabelAuxiliary] = "true"
+	}
+	return mocks
+}
+
 // CombineMocks is helper function that helps exchanges to build mocks.
 func CombineMocks(e []ExchangeMock, f MockableFunc) ([]*smocker.Mock, error) {
 	var mocks []*smocker.Mock
 	for _, ex := range e {
-		m, err := f(ex)
+		m, err := buildMock(ex, f)
 		if err != nil {
-			return nil, fmt.Errorf("failed to build mock: %w", err)
+			return nil, err
 		}
 		if m != nil {
-			mocks = append(mocks, m)
+			rm, err := applyRateLimit(ex, m)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range rm {
+				if err := applyDelay(ex, m); err != nil {
+					return nil, err
+				}
+			}
+			if len(ex.Sequence) > 0 {
+				// The mock responds after the sequence only.
+				auxiliary(rm)
+			}
+			labelMocks(rm, ex)
+			mocks = append(mocks, rm...)
+		}
+		sm, err := buildSequence(ex, f)
+		if err != nil {
+			return nil, err
+		}
+		labelMocks(sm, ex)
+		mocks = append(mocks, sm...)
+	}
+	return mocks, nil
+}
+
+// AggregateMocks is helper function that helps exchanges to build a single mock
+// for all exchange mocks, e.g. for all-tickers endpoints. The mock fails with the
+// error of the first exchange mock that doesn't respond with `200 OK` or has an
+// error set. Faults, rate limits and delays are taken from the first exchange
+// mock that has them, and sequences are ignored.
+func AggregateMocks(e []ExchangeMock, f func(e []ExchangeMock) (*smocker.Mock, error)) ([]*smocker.Mock, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	faulty := make([]ExchangeMock, len(e))
+	for i, ex := range e {
+		faulty[i] = ex.withPriceFaults()
+	}
+	m, err := f(faulty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build mock: %w", err)
+	}
+	if m == nil {
+		return nil, nil
+	}
+	if ex, ok := findMock(e, func(ex ExchangeMock) bool {
+		return ex.Error != nil || ex.StatusCode != http.StatusOK
+	}); ok {
+		if m.Response != nil {
+			m.Response.Status = ex.StatusCode
+		}
+		if err := applyError(ex, m); err != nil {
+			return nil, err
 		}
 	}
+	if ex, ok := findMock(e, func(ex ExchangeMock) bool { return len(ex.Faults) > 0 }); ok {
+		if err := applyFaults(ex, m); err != nil {
+			return nil, err
+		}
+	}
+	rateLimited, _ := findMock(e, func(ex ExchangeMock) bool { return ex.RateLimit != nil })
+	mocks, err := applyRateLimit(rateLimited, m)
+	if err != nil {
+		return nil, err
+	}
+	delayed, _ := findMock(e, func(ex ExchangeMock) bool { return ex.Delay != nil || ex.Latency != "" })
+	for _, m := range mocks {
+		if err := applyDelay(delayed, m); err != nil {
+			return nil, err
+		}
+	}
+	labelMocks(mocks, e...)
 	return mocks, nil
 }
 
+// findMock returns the first exchange mock that satisfies fn. If there is none,
+// it returns the first exchange mock and false.
+func findMock(e []ExchangeMock, fn func(ExchangeMock) bool) (ExchangeMock, bool) {
+	for _, ex := range e {
+		if fn(ex) {
+			return ex, true
+		}
+	}
+	return e[0], false
+}
+
+// buildMock builds the mock for the exchange mock with its error and faults applied.
+func buildMock(e ExchangeMock, f MockableFunc) (*smocker.Mock, error) {
+	m, err := f(e.withPriceFaults())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build mock: %w", err)
+	}
+	if m == nil {
+		return nil, nil
+	}
+	if err := applyError(e, m); err != nil {
+		return nil, err
+	}
+	if err := applyFaults(e, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var exchangesMu sync.RWMutex
+
 var exchanges = map[string]Mockable{
 	"balancerV2": BalancerV2{},
 	"binance":    Binance{},
@@ -48,6 +196,7 @@ var exchanges = map[string]Mockable{
 	"kraken":     Kraken{},
 	"kucoin":     KuCoin{},
 	"okex":       Okex{},
+	"poloniex":   Poloniex{},
 	"rocketpool": RocketPool{},
 	"sdai":       SDAI{},
 	"sushiswap":  Sushiswap{},
@@ -57,6 +206,50 @@ var exchanges = map[string]Mockable{
 	"wsteth":     WSTETH{},
 }
 
+// Register adds a custom origin, so it can be mocked with NewExchange(name) like
+// the built-in ones. It fails if the name is already registered.
+func Register(name string, m Mockable) error {
+	if name == "" {
+		return fmt.Errorf("exchange name is required")
+	}
+	if m == nil {
+		return fmt.Errorf("nil mockable for exchange %s", name)
+	}
+	exchangesMu.Lock()
+	defer exchangesMu.Unlock()
+	if _, ok := exchanges[name]; ok {
+		return fmt.Errorf("exchange %s is already registered", name)
+	}
+	exchanges[name] = m
+	return nil
+}
+
+// Unregister removes the origin with given name, it does nothing if the name is not registered.
+func Unregister(name string) {
+	exchangesMu.Lock()
+	defer exchangesMu.Unlock()
+	delete(exchanges, name)
+}
+
+func lookupExchange(name string) (Mockable, bool) {
+	exchangesMu.RLock()
+	defer exchangesMu.RUnlock()
+	ex, ok := exchanges[name]
+	return ex, ok
+}
+
+// Names returns the sorted names of registered exchanges.
+func Names() []string {
+	exchangesMu.RLock()
+	defer exchangesMu.RUnlock()
+	names := make([]string, 0, len(exchanges))
+	for name := range exchanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Symbol represents an asset pair.
 type Symbol struct {
 	Base  string
@@ -91,12 +284,33 @@ type ExchangeMock struct {
 	Name       string
 	StatusCode int
 	Symbol     Symbol
-	Price      float64
-	Volume     float64
-	Ask        float64
-	Bid        float64
-	Timestamp  time.Time
-	Custom     map[string]any
+	Price      Decimal
+	Volume     Decimal
+	Ask        Decimal
+	Bid        Decimal
+	// Decimals is the number of decimals origins emit for prices and volumes,
+	// nil keeps origins' defaults. See WithDecimals.
+	Decimals *int
+	// Error is the error response set by WithError.
+	Error *ExchangeError
+	// RateLimit is the rate limiter set by WithRateLimit.
+	RateLimit *RateLimit
+	// Faults are the corruptions of responses set by WithFault.
+	Faults []Fault
+	// OrderBook is the order book set by WithOrderBook.
+	OrderBook *OrderBook
+	// Candles is the price series set by WithCandles.
+	Candles *CandleSeries
+	// Trades are the recent trades set by WithTrades.
+	Trades []Trade
+	// Delay is the delay of responses set by WithDelay.
+	Delay *smocker.Delay
+	// Latency is the latency profile set by WithLatency, it overrides Delay.
+	Latency LatencyProfile
+	// Sequence is the sequence of responses set by WithSequence.
+	Sequence  []Step
+	Timestamp time.Time
+	Custom    map[string]any
 }
 
 func NewExchange(name string) *ExchangeMock {
@@ -119,25 +333,56 @@ func (e *ExchangeMock) WithSymbol(symbol string) *ExchangeMock {
 }
 
 func (e *ExchangeMock) WithPrice(price float64) *ExchangeMock {
+	e.Price = NewDecimal(price)
+	return e
+}
+
+// WithPriceDecimal sets the exact price, e.g. `WithPriceDecimal(MustParseDecimal("0.000000012345"))`.
+func (e *ExchangeMock) WithPriceDecimal(price Decimal) *ExchangeMock {
 	e.Price = price
 	return e
 }
 
 func (e *ExchangeMock) WithVolume(volume float64) *ExchangeMock {
+	e.Volume = NewDecimal(volume)
+	return e
+}
+
+// WithVolumeDecimal sets the exact volume, e.g. `WithVolumeDecimal(MustParseDecimal("0.000000012345"))`.
+func (e *ExchangeMock) WithVolumeDecimal(volume Decimal) *ExchangeMock {
 	e.Volume = volume
 	return e
 }
 
 func (e *ExchangeMock) WithAsk(ask float64) *ExchangeMock {
+	e.Ask = NewDecimal(ask)
+	return e
+}
+
+// WithAskDecimal sets the exact ask, e.g. `WithAskDecimal(MustParseDecimal("0.000000012345"))`.
+func (e *ExchangeMock) WithAskDecimal(ask Decimal) *ExchangeMock {
 	e.Ask = ask
 	return e
 }
 
 func (e *ExchangeMock) WithBid(bid float64) *ExchangeMock {
+	e.Bid = NewDecimal(bid)
+	return e
+}
+
+// WithBidDecimal sets the exact bid, e.g. `WithBidDecimal(MustParseDecimal("0.000000012345"))`.
+func (e *ExchangeMock) WithBidDecimal(bid Decimal) *ExchangeMock {
 	e.Bid = bid
 	return e
 }
 
+// WithDecimals sets the number of decimals emitted for prices and volumes instead
+// of the origin's default (e.g. 8 for Binance), negative n emits all significant decimals.
+func (e *ExchangeMock) WithDecimals(n int) *ExchangeMock {
+	e.Decimals = &n
+	return e
+}
+
 func (e *ExchangeMock) WithTime(timestamp time.Time) *ExchangeMock {
 	e.Timestamp = timestamp
 	return e
@@ -161,11 +406,11 @@ func (e *ExchangeMock) GetFunctionData(funcName string) ([]FunctionData, error)
 }
 
 func BuildMocksForExchanges(exchangeName string, e []ExchangeMock) ([]*smocker.Mock, error) {
-	ex, ok := exchanges[exchangeName]
+	ex, ok := lookupExchange(exchangeName)
 	if !ok {
 		return nil, fmt.Errorf("failed to find exchange name %s", exchangeName)
 	}
-	mocks, err := ex.BuildMocks(e)
+	mocks, err := ex.BuildMocks(withDecimals(e))
 	if err != nil {
 		return nil, err
 	}
@@ -174,6 +419,34 @@ func BuildMocksForExchanges(exchangeName string, e []ExchangeMock) ([]*smocker.M
 		if mErr != nil {
 			return nil, mErr
 		}
+		// Mocks of custom origins may be built without CombineMocks.
+		if m.Labels == nil {
+			m.Labels = map[string]string{LabelOrigin: exchangeName}
+		}
 	}
 	return mocks, nil
 }
+
+// withDecimals applies the number of decimals set on exchange mocks to their values.
+func withDecimals(e []ExchangeMock) []ExchangeMock {
+	res := make([]ExchangeMock, len(e))
+	for i, ex := range e {
+		if ex.Decimals != nil {
+			ex.Price = ex.Price.WithDecimals(*ex.Decimals)
+			ex.Volume = ex.Volume.WithDecimals(*ex.Decimals)
+			ex.Ask = ex.Ask.WithDecimals(*ex.Decimals)
+			ex.Bid = ex.Bid.WithDecimals(*ex.Decimals)
+			if ex.OrderBook != nil {
+				book := ex.OrderBook.withDecimals(*ex.Decimals)
+				ex.OrderBook = &book
+			}
+			if ex.Candles != nil {
+				candles := ex.Candles.withDecimals(*ex.Decimals)
+				ex.Candles = &candles
+			}
+			ex.Trades = withTradesDecimals(ex.Trades, *ex.Decimals)
+		}
+		res[i] = ex
+	}
+	return res
+}
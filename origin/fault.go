@@ -0,0 +1,265 @@
+package origin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// Fault corrupts an otherwise valid response of an origin, see ExchangeMock.WithFault.
+type Fault string
+
+const (
+	// FaultTruncatedJSON cuts the response body in half.
+	FaultTruncatedJSON Fault = "truncated_json"
+	// FaultWrongContentType responds with the `text/plain` content type.
+	FaultWrongContentType Fault = "wrong_content_type"
+	// FaultHTMLMaintenance responds with an HTML maintenance page and `503 Service Unavailable`.
+	FaultHTMLMaintenance Fault = "html_maintenance"
+	// FaultEmptyBody responds with an empty body and `200 OK`.
+	FaultEmptyBody Fault = "empty_body"
+	// FaultNaNPrice emits prices as `NaN`. Like other price faults, it also
+	// applies to prices of candles, trades and order book levels.
+	FaultNaNPrice Fault = "nan_price"
+	// FaultNegativePrice emits negated prices, -1 if the price is 0.
+	FaultNegativePrice Fault = "negative_price"
+	// FaultZeroPrice emits 0 prices.
+	FaultZeroPrice Fault = "zero_price"
+	// FaultMissingFields removes fields with prices from the JSON body. Values
+	// of positional arrays are removed from the price to the end.
+	FaultMissingFields Fault = "missing_fields"
+	// FaultExtraFields adds an unknown field to every JSON object and an unknown
+	// value to every non-empty array of scalars.
+	FaultExtraFields Fault = "extra_fields"
+)
+
+// priceMarker is emitted in place of the price to find its fields, see FaultMissingFields.
+const priceMarker = "-9876543210.0123456789"
+
+const maintenancePage = `<!DOCTYPE html>
+<html>
+<head><title>503 Service Temporarily Unavailable</title></head>
+<body>
+<h1>We'll be back soon!</h1>
+<p>The system is under maintenance. Please try again later.</p>
+</body>
+</html>`
+
+// WithFault makes every endpoint of the exchange respond with corrupted
+// responses, faults are applied in order.
+func (e *ExchangeMock) WithFault(faults ...Fault) *ExchangeMock {
+	e.Faults = append(e.Faults, faults...)
+	return e
+}
+
+// withPriceFaults returns the exchange mock with the prices changed by its
+// faults, including prices of candles, trades and order book levels. Bid and
+// ask prices are changed only if they are set.
+func (e ExchangeMock) withPriceFaults() ExchangeMock {
+	change := func(f func(d Decimal) Decimal) {
+		e.Price = f(e.Price)
+		if !e.Bid.IsZero() {
+			e.Bid = f(e.Bid)
+		}
+		if !e.Ask.IsZero() {
+			e.Ask = f(e.Ask)
+		}
+		if e.OrderBook != nil {
+			book := OrderBook{
+				Bids: make([]OrderBookLevel, len(e.OrderBook.Bids)),
+				Asks: make([]OrderBookLevel, len(e.OrderBook.Asks)),
+			}
+			for i, l := range e.OrderBook.Bids {
+				book.Bids[i] = OrderBookLevel{Price: f(l.Price), Amount: l.Amount}
+			}
+			for i, l := range e.OrderBook.Asks {
+				book.Asks[i] = OrderBookLevel{Price: f(l.Price), Amount: l.Amount}
+			}
+			e.OrderBook = &book
+		}
+		if e.Candles != nil {
+			series := CandleSeries{Interval: e.Candles.Interval, Candles: make([]Candle, len(e.Candles.Candles))}
+			for i, c := range e.Candles.Candles {
+				c.Open, c.High, c.Low, c.Close = f(c.Open), f(c.High), f(c.Low), f(c.Close)
+				series.Candles[i] = c
+			}
+			e.Candles = &series
+		}
+		if len(e.Trades) > 0 {
+			trades := make([]Trade, len(e.Trades))
+			for i, t := range e.Trades {
+				t.Price = f(t.Price)
+				trades[i] = t
+			}
+			e.Trades = trades
+		}
+	}
+	for _, f := range e.Faults {
+		switch f {
+		case FaultNaNPrice:
+			change(func(d Decimal) Decimal {
+				d.text = "NaN"
+				return d
+			})
+		case FaultNegativePrice:
+			change(func(d Decimal) Decimal {
+				if d.IsZero() {
+					return Decimal{r: big.NewRat(-1, 1), decimals: d.decimals}
+				}
+				return d.Mul(Decimal{r: big.NewRat(-1, 1)})
+			})
+		case FaultZeroPrice:
+			change(func(d Decimal) Decimal {
+				return Decimal{r: new(big.Rat), decimals: d.decimals}
+			})
+		case FaultMissingFields:
+			change(func(d Decimal) Decimal {
+				d.text = priceMarker
+				return d
+			})
+		}
+	}
+	return e
+}
+
+// applyFaults corrupts the response of the mock built for the exchange mock.
+// Faults that change prices or the body fail for dynamic responses, e.g. of
+// JSON-RPC origins, as they would have no effect.
+func applyFaults(e ExchangeMock, m *smocker.Mock) error {
+	static := m.Response != nil
+	for _, f := range e.Faults {
+		switch f {
+		case FaultNaNPrice, FaultNegativePrice, FaultZeroPrice:
+			// Applied before the mock is built.
+			if !static {
+				return fmt.Errorf("fault %s requires a static response", f)
+			}
+		case FaultHTMLMaintenance:
+			setFaultResponse(m, http.StatusServiceUnavailable, "text/html; charset=utf-8", maintenancePage)
+		case FaultEmptyBody:
+			setFaultResponse(m, http.StatusOK, "application/json", "")
+		case FaultTruncatedJSON:
+			if m.Response == nil {
+				return fmt.Errorf("fault %s requires a static response", f)
+			}
+			m.Response.Body = m.Response.Body[:len(m.Response.Body)/2]
+		case FaultWrongContentType:
+			if m.Response == nil {
+				return fmt.Errorf("fault %s requires a static response", f)
+			}
+			if m.Response.Headers == nil {
+				m.Response.Headers = make(smocker.MapStringSlice)
+			}
+			m.Response.Headers["Content-Type"] = []string{"text/plain; charset=utf-8"}
+		case FaultMissingFields:
+			if err := transformJSON(m, f, dropPriceFields); err != nil {
+				return err
+			}
+		case FaultExtraFields:
+			if err := transformJSON(m, f, addExtraFields); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown fault %s", f)
+		}
+	}
+	return nil
+}
+
+// setFaultResponse replaces the response of the mock.
+func setFaultResponse(m *smocker.Mock, statusCode int, contentType, body string) {
+	m.DynamicResponse = nil
+	m.Proxy = nil
+	m.Response = &smocker.MockResponse{
+		Status: statusCode,
+		Headers: map[string]smocker.StringSlice{
+			"Content-Type": []string{
+				contentType,
+			},
+		},
+		Body: body,
+	}
+}
+
+// transformJSON replaces the JSON body of the mock with the result of fn. Keys
+// of objects are sorted in the new body.
+func transformJSON(m *smocker.Mock, f Fault, fn func(v any) any) error {
+	if m.Response == nil {
+		return fmt.Errorf("fault %s requires a static response", f)
+	}
+	if m.Response.Body == "" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewBufferString(m.Response.Body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("fault %s requires a JSON response: %w", f, err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(fn(v)); err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	m.Response.Body = string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return nil
+}
+
+func isPriceMarker(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return v == priceMarker
+	case json.Number:
+		return string(v) == priceMarker
+	}
+	return false
+}
+
+func dropPriceFields(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, el := range v {
+			if isPriceMarker(el) {
+				delete(v, k)
+				continue
+			}
+			v[k] = dropPriceFields(el)
+		}
+	case []any:
+		for i, el := range v {
+			if isPriceMarker(el) {
+				return v[:i]
+			}
+			v[i] = dropPriceFields(el)
+		}
+	}
+	return v
+}
+
+func addExtraFields(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, el := range v {
+			v[k] = addExtraFields(el)
+		}
+		v["unknownField"] = "unexpected"
+	case []any:
+		scalars := len(v) > 0
+		for i, el := range v {
+			switch el.(type) {
+			case map[string]any, []any:
+				scalars = false
+			}
+			v[i] = addExtraFields(el)
+		}
+		if scalars {
+			return append(v, "unexpected")
+		}
+	}
+	return v
+}
@@ -0,0 +1,132 @@
+package origin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func TestFaultsAllOrigins(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	exchange := func(name string, faults ...Fault) []ExchangeMock {
+		return []ExchangeMock{*NewExchange(name).
+			WithSymbol("ETH/BTC").
+			WithPrice(1.2345).
+			WithBid(1.2345).
+			WithAsk(1.2345).
+			WithVolume(2).
+			WithOrderBook(OrderBook{
+				Bids: []OrderBookLevel{NewOrderBookLevel(1.2345, 2)},
+				Asks: []OrderBookLevel{NewOrderBookLevel(1.2345, 2)},
+			}).
+			WithCandles(time.Minute, NewCandle(start, 1.2345, 1.2345, 1.2345, 1.2345, 2)).
+			WithTrades(NewTrade(start, 1.2345, 2, TradeBuy)).
+			WithFault(faults...)}
+	}
+	const price = "1.2345"
+
+	// Checks of mocks with prices.
+	priceChecks := map[Fault]func(t *testing.T, m *smocker.Mock){
+		FaultNaNPrice: func(t *testing.T, m *smocker.Mock) {
+			require.NotContains(t, m.Response.Body, price)
+			require.Contains(t, m.Response.Body, "NaN")
+		},
+		FaultNegativePrice: func(t *testing.T, m *smocker.Mock) {
+			require.Equal(t, strings.Count(m.Response.Body, price), strings.Count(m.Response.Body, "-"+price))
+		},
+		FaultZeroPrice: func(t *testing.T, m *smocker.Mock) {
+			require.NotContains(t, m.Response.Body, price)
+		},
+		FaultMissingFields: func(t *testing.T, m *smocker.Mock) {
+			require.True(t, json.Valid([]byte(m.Response.Body)))
+			require.NotContains(t, m.Response.Body, price)
+			require.NotContains(t, m.Response.Body, priceMarker)
+		},
+	}
+	// Checks of all mocks.
+	checks := map[Fault]func(t *testing.T, m *smocker.Mock){
+		FaultTruncatedJSON: func(t *testing.T, m *smocker.Mock) {
+			require.False(t, json.Valid([]byte(m.Response.Body)))
+		},
+		FaultWrongContentType: func(t *testing.T, m *smocker.Mock) {
+			require.Equal(t, smocker.StringSlice{"text/plain; charset=utf-8"}, m.Response.Headers["Content-Type"])
+		},
+		FaultHTMLMaintenance: func(t *testing.T, m *smocker.Mock) {
+			require.Equal(t, http.StatusServiceUnavailable, m.Response.Status)
+			require.Contains(t, m.Response.Body, "<html>")
+		},
+		FaultEmptyBody: func(t *testing.T, m *smocker.Mock) {
+			require.Empty(t, m.Response.Body)
+		},
+		FaultExtraFields: func(t *testing.T, m *smocker.Mock) {
+			require.Contains(t, m.Response.Body, `"unexpected"`)
+		},
+	}
+
+	for _, name := range []string{
+		"binance", "bitfinex", "bitstamp", "coinbase", "gemini", "hitbtc",
+		"huobi", "kraken", "kucoin", "okex", "poloniex", "upbit",
+	} {
+		baseline, err := BuildMocksForExchanges(name, exchange(name))
+		require.NoError(t, err, name)
+		require.Contains(t, baseline[0].Response.Body, price, name)
+
+		for fault, check := range checks {
+			mocks, err := BuildMocksForExchanges(name, exchange(name, fault))
+			require.NoError(t, err, "%s: %s", name, fault)
+			require.Len(t, mocks, len(baseline), "%s: %s", name, fault)
+			for _, m := range mocks {
+				t.Run(name+"/"+string(fault)+"/"+m.Request.Path.Value, func(t *testing.T) {
+					require.NotNil(t, m.Response)
+					check(t, m)
+				})
+			}
+		}
+		for fault, check := range priceChecks {
+			mocks, err := BuildMocksForExchanges(name, exchange(name, fault))
+			require.NoError(t, err, "%s: %s", name, fault)
+			require.Len(t, mocks, len(baseline), "%s: %s", name, fault)
+			for i, m := range mocks {
+				if !strings.Contains(baseline[i].Response.Body, price) {
+					continue
+				}
+				t.Run(name+"/"+string(fault)+"/"+m.Request.Path.Value, func(t *testing.T) {
+					check(t, m)
+				})
+			}
+		}
+	}
+
+	// Prices and bodies of dynamic responses can't be corrupted, other faults replace them.
+	for _, fault := range []Fault{
+		FaultTruncatedJSON, FaultWrongContentType, FaultNaNPrice, FaultNegativePrice,
+		FaultZeroPrice, FaultMissingFields, FaultExtraFields,
+	} {
+		_, err := BuildMocksForExchanges("ethrpc", []ExchangeMock{
+			*NewExchange("ethrpc").WithBlockNumber(100).WithFault(fault),
+		})
+		require.ErrorContains(t, err, "requires a static response", fault)
+	}
+	for fault, check := range checks {
+		if fault != FaultHTMLMaintenance && fault != FaultEmptyBody {
+			continue
+		}
+		mocks, err := BuildMocksForExchanges("ethrpc", []ExchangeMock{
+			*NewExchange("ethrpc").WithBlockNumber(100).WithFault(fault),
+		})
+		require.NoError(t, err, fault)
+		for _, m := range mocks {
+			require.NotNil(t, m.Response, fault)
+			check(t, m)
+		}
+	}
+
+	_, err := BuildMocksForExchanges("binance", []ExchangeMock{*NewExchange("binance").WithFault("unknown")})
+	require.Error(t, err)
+}
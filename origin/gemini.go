@@ -2,6 +2,7 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/chronicleprotocol/infestor/smocker"
@@ -13,6 +14,13 @@ func (g Gemini) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	return CombineMocks(e, g.build)
 }
 
+func (g Gemini) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusBadRequest, "InvalidSymbol", "Supplied value is not a valid symbol")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"result":"error","reason":%s,"message":%s}`,
+		jsonString(ee.Code), jsonString(ee.Message)))
+	return nil
+}
+
 func (g Gemini) build(e ExchangeMock) (*smocker.Mock, error) {
 	symbol := strings.ToLower(e.Symbol.Format("%s%s"))
 	body := `{
@@ -2,6 +2,7 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -12,6 +13,17 @@ func (h HitBTC) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	return CombineMocks(e, h.build)
 }
 
+func (h HitBTC) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusBadRequest, "2001", "Symbol not found")
+	code, err := ee.numericCode()
+	if err != nil {
+		return err
+	}
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"error":{"code":%d,"message":%s,"description":""}}`,
+		code, jsonString(ee.Message)))
+	return nil
+}
+
 func (h HitBTC) build(e ExchangeMock) (*smocker.Mock, error) {
 	symbol := e.Symbol.Format("%s%s")
 	body := `{
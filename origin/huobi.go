@@ -2,7 +2,9 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -18,29 +20,33 @@ func (h Huobi) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	if err != nil {
 		return nil, err
 	}
-	tickers, err := CombineMocks(e, h.buildTickers)
+	tickers, err := AggregateMocks(e, h.buildTickers)
 	if err != nil {
 		return nil, err
 	}
-	return append(mocksOne, tickers...), nil
+	klines, err := buildCandleMocks(e, huobiPeriods, true, h.buildKlines)
+	if err != nil {
+		return nil, err
+	}
+	mocks := append(mocksOne, auxiliary(tickers)...)
+	return append(mocks, klines...), nil
 }
 
-func (h Huobi) buildTickers(e ExchangeMock) (*smocker.Mock, error) {
-	symbol := strings.ToLower(e.Symbol.Format("%s%s"))
-	price := e.Price
-	if e.Bid != 0 {
-		price = e.Bid
-	}
-	body := `{
-		"status": "ok",
-		"ts": %d,
-		"data": [
-			{
+func (h Huobi) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusOK, "invalid-parameter", "invalid symbol")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"status":"error","err-code":%s,"err-msg":%s,"data":null}`,
+		jsonString(ee.Code), jsonString(ee.Message)))
+	return nil
+}
+
+// buildTickers builds tickers of all symbols.
+func (h Huobi) buildTickers(e []ExchangeMock) (*smocker.Mock, error) {
+	ticker := `{
 				"symbol":"%s",
-				"open":%f,     
-				"high":%f,     
-				"low":%f,        
-				"close":%f,     
+				"open":%f,
+				"high":%f,
+				"low":%f,
+				"close":%f,
 				"amount":36551302.17544405,
 				"vol":%f,
 				"count":1709,
@@ -48,23 +54,42 @@ func (h Huobi) buildTickers(e ExchangeMock) (*smocker.Mock, error) {
 				"bidSize":54300.341,
 				"ask":%f,
 				"askSize":1923.4879
-				}
+			}`
+	body := `{
+		"status": "ok",
+		"ts": %d,
+		"data": [
+			%s
 		]
 	}`
 
+	tickers := make([]string, 0, len(e))
+	var ts int64
+	for _, ex := range e {
+		symbol := strings.ToLower(ex.Symbol.Format("%s%s"))
+		price := ex.Price
+		if !ex.Bid.IsZero() {
+			price = ex.Bid
+		}
+		tickers = append(tickers, fmt.Sprintf(ticker, symbol, price, price, price, price, ex.Volume, price, ex.Ask))
+		if t := ex.Timestamp.UnixMilli(); t > ts {
+			ts = t
+		}
+	}
+
 	return &smocker.Mock{
 		Request: smocker.MockRequest{
 			Method: smocker.ShouldEqual("GET"),
 			Path:   smocker.ShouldEqual("/market/tickers"),
 		},
 		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
+			Status: http.StatusOK,
 			Headers: map[string]smocker.StringSlice{
 				"Content-Type": []string{
 					"application/json",
 				},
 			},
-			Body: fmt.Sprintf(body, e.Timestamp.UnixMilli(), symbol, price, price, price, price, e.Volume, price, e.Ask),
+			Body: fmt.Sprintf(body, ts, strings.Join(tickers, ",\n\t\t\t")),
 		},
 	}, nil
 }
@@ -72,7 +97,7 @@ func (h Huobi) buildTickers(e ExchangeMock) (*smocker.Mock, error) {
 func (h Huobi) buildForOne(e ExchangeMock) (*smocker.Mock, error) {
 	symbol := strings.ToLower(e.Symbol.Format("%s%s"))
 	price := e.Price
-	if e.Bid != 0 {
+	if !e.Bid.IsZero() {
 		price = e.Bid
 	}
 	body := `{
@@ -112,3 +137,65 @@ func (h Huobi) buildForOne(e ExchangeMock) (*smocker.Mock, error) {
 		},
 	}, nil
 }
+
+var huobiPeriods = []candleInterval{
+	{"1min", time.Minute},
+	{"5min", 5 * time.Minute},
+	{"15min", 15 * time.Minute},
+	{"30min", 30 * time.Minute},
+	{"60min", time.Hour},
+	{"4hour", 4 * time.Hour},
+	{"1day", 24 * time.Hour},
+}
+
+// buildKlines builds klines for the period, newest first. Requests without
+// a size get the last 150 klines.
+func (h Huobi) buildKlines(interval candleInterval, size int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		candles, ok := e.candles(interval.duration, size, 150)
+		if !ok {
+			return nil, nil
+		}
+		symbol := strings.ToLower(e.Symbol.Format("%s%s"))
+		kline := `{"id":%d,"open":%f,"close":%f,"low":%f,"high":%f,"amount":%f,"vol":%f,"count":1}`
+		klines := make([]string, 0, len(candles))
+		for i := len(candles) - 1; i >= 0; i-- {
+			c := candles[i]
+			klines = append(klines, fmt.Sprintf(kline,
+				c.Time.Unix(),
+				c.Open,
+				c.Close,
+				c.Low,
+				c.High,
+				c.Volume,
+				c.Volume.Mul(c.Close)))
+		}
+		body := `{"ch":"market.%s.kline.%s","status":"ok","ts":%d,"data":[%s]}`
+		query := map[string]smocker.StringMatcherSlice{
+			"symbol": []smocker.StringMatcher{
+				smocker.ShouldEqual(symbol),
+			},
+			"period": []smocker.StringMatcher{
+				smocker.ShouldEqual(interval.name),
+			},
+			"size": limitMatchers(size, len(candles)),
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method:      smocker.ShouldEqual("GET"),
+				Path:        smocker.ShouldEqual("/market/history/kline"),
+				QueryParams: query,
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf(body, symbol, interval.name, e.Timestamp.UnixMilli(), strings.Join(klines, ",")),
+			},
+		}, nil
+	}
+}
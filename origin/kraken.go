@@ -2,6 +2,10 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -9,19 +13,66 @@ import (
 type Kraken struct{}
 
 func (k Kraken) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
-	mocksOne, err := CombineMocks(e, k.build)
+	mocks, err := CombineMocks(e, k.build)
 	if err != nil {
 		return nil, err
 	}
-	other, err := CombineMocks(e, k.buildWithSlash)
+	assetPairs, err := AggregateMocks(e, k.buildAssetPairs)
 	if err != nil {
 		return nil, err
 	}
-	return append(mocksOne, other...), nil
+	// Mocks for single pairs are added after all-pairs one, so they take precedence.
+	assetPairsOne, err := CombineMocks(e, k.buildAssetPairsForOne)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := AggregateMocks(e, k.buildAssets)
+	if err != nil {
+		return nil, err
+	}
+	depth, err := CombineMocks(e, k.buildDepth)
+	if err != nil {
+		return nil, err
+	}
+	ohlc, err := buildCandleMocks(e, krakenIntervals, false, k.buildOHLC)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := buildLimitMocks(e, true, k.buildTrades)
+	if err != nil {
+		return nil, err
+	}
+	mocks = append(mocks, depth...)
+	mocks = append(mocks, ohlc...)
+	mocks = append(mocks, trades...)
+	mocks = append(mocks, auxiliary(assetPairs)...)
+	mocks = append(mocks, auxiliary(assetPairsOne)...)
+	return append(mocks, auxiliary(assets)...), nil
+}
+
+func (k Kraken) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	// Kraken errors are `<severity><category>:<message>` strings, the code is the prefix.
+	ee := e.Error.withDefaults(http.StatusOK, "EQuery", "Unknown asset pair")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"error":[%s]}`, jsonString(ee.Code+":"+ee.Message)))
+	return nil
 }
 
-func (k Kraken) buildWithSlash(e ExchangeMock) (*smocker.Mock, error) {
-	symbol := e.Symbol.Format("%s/%s")
+// BuildRateLimitResponse responds with the Kraken rate limit error, which is
+// sent with `200 OK` unless RateLimit.StatusCode is set.
+func (k Kraken) BuildRateLimitResponse(e ExchangeMock, m *smocker.Mock, n uint) error {
+	if !e.RateLimit.limited(n) {
+		return nil
+	}
+	statusCode := e.RateLimit.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	setErrorResponse(m, statusCode, `{"error":["EAPI:Rate limit exceeded"]}`)
+	return nil
+}
+
+func (k Kraken) build(e ExchangeMock) (*smocker.Mock, error) {
+	pair := k.pair(e.Symbol)
 	body := `{
  "error": [],
  "result": {
@@ -54,7 +105,7 @@ func (k Kraken) buildWithSlash(e ExchangeMock) (*smocker.Mock, error) {
 			Path:   smocker.ShouldEqual("/0/public/Ticker"),
 			QueryParams: map[string]smocker.StringMatcherSlice{
 				"pair": []smocker.StringMatcher{
-					smocker.ShouldEqual(symbol),
+					k.pairMatcher(e.Symbol),
 				},
 			},
 		},
@@ -65,46 +116,214 @@ func (k Kraken) buildWithSlash(e ExchangeMock) (*smocker.Mock, error) {
 					"application/json",
 				},
 			},
-			Body: fmt.Sprintf(body, symbol, e.Ask, e.Bid, e.Price, e.Volume),
+			Body: fmt.Sprintf(body, pair.key, e.Ask, e.Bid, e.Price, e.Volume),
 		},
 	}, nil
 }
 
-func (k Kraken) build(e ExchangeMock) (*smocker.Mock, error) {
-	symbol := e.Symbol.Format("%s%s")
-	body := `{
- "error": [],
- "result": {
-	 "%s": {
-		 "a": [
-			 "%f",
-			 "10",
-			 "10.000"
-		 ],
-		 "b": [
-			 "%f",
-			 "2",
-			 "2.000"
-		 ],
-		 "c": [
-			 "%f",
-			 "0.22651150"
-		 ],
-		 "v": [
-			 "%f",
-			 "5803.57144830"
-		 ]
-	 }
- }
+func (k Kraken) buildDepth(e ExchangeMock) (*smocker.Mock, error) {
+	if e.OrderBook == nil {
+		return nil, nil
+	}
+	book := e.OrderBook.sorted()
+	level := fmt.Sprintf(`["%%f","%%f",%d]`, e.Timestamp.Unix())
+	body := `{"error": [], "result": {%s: {"asks": %s, "bids": %s}}}`
+
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual("/0/public/Depth"),
+			QueryParams: map[string]smocker.StringMatcherSlice{
+				"pair": []smocker.StringMatcher{
+					k.pairMatcher(e.Symbol),
+				},
+			},
+		},
+		Response: &smocker.MockResponse{
+			Status: e.StatusCode,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf(body,
+				jsonString(k.pair(e.Symbol).key),
+				formatLevels(book.Asks, level),
+				formatLevels(book.Bids, level)),
+		},
+	}, nil
+}
+
+// krakenIntervals are in minutes, the first one is the default.
+var krakenIntervals = []candleInterval{
+	{"1", time.Minute},
+	{"5", 5 * time.Minute},
+	{"15", 15 * time.Minute},
+	{"30", 30 * time.Minute},
+	{"60", time.Hour},
+	{"240", 4 * time.Hour},
+	{"1440", 24 * time.Hour},
+}
+
+func (k Kraken) buildOHLC(interval candleInterval, _ int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		candles, ok := e.candles(interval.duration, 0, 720)
+		if !ok {
+			return nil, nil
+		}
+		row := `[%d,"%f","%f","%f","%f","%f","%f",1]`
+		rows := make([]string, 0, len(candles))
+		for _, c := range candles {
+			rows = append(rows, fmt.Sprintf(row, c.Time.Unix(), c.Open, c.High, c.Low, c.Close, c.Close, c.Volume))
+		}
+		body := `{"error": [], "result": {%s: [%s], "last": %d}}`
+		query := map[string]smocker.StringMatcherSlice{
+			"pair": []smocker.StringMatcher{
+				k.pairMatcher(e.Symbol),
+			},
+		}
+		// Requests without an interval get the default one, which is mocked
+		// first, so mocks of other intervals take precedence.
+		if interval != krakenIntervals[0] {
+			query["interval"] = []smocker.StringMatcher{
+				smocker.ShouldEqual(interval.name),
+			}
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method:      smocker.ShouldEqual("GET"),
+				Path:        smocker.ShouldEqual("/0/public/OHLC"),
+				QueryParams: query,
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf(body,
+					jsonString(k.pair(e.Symbol).key),
+					strings.Join(rows, ","),
+					candles[len(candles)-1].Time.Unix()),
+			},
+		}, nil
+	}
+}
+
+// buildTrades builds recent trades, requests without a count get the last 1000.
+func (k Kraken) buildTrades(count int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		trades, ok := e.trades(count, 1000)
+		if !ok {
+			return nil, nil
+		}
+		row := `["%f","%f",%.4f,"%s","l","",%d]`
+		rows := make([]string, 0, len(trades))
+		for _, t := range trades {
+			side := "b"
+			if t.Side == TradeSell {
+				side = "s"
+			}
+			rows = append(rows, fmt.Sprintf(row,
+				t.Price,
+				t.Amount,
+				float64(t.Time.UnixNano())/float64(time.Second),
+				side,
+				t.ID))
+		}
+		body := `{"error": [], "result": {%s: [%s], "last": "%d"}}`
+		query := map[string]smocker.StringMatcherSlice{
+			"pair": []smocker.StringMatcher{
+				k.pairMatcher(e.Symbol),
+			},
+			"count": limitMatchers(count, len(trades)),
+		}
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method:      smocker.ShouldEqual("GET"),
+				Path:        smocker.ShouldEqual("/0/public/Trades"),
+				QueryParams: query,
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf(body,
+					jsonString(k.pair(e.Symbol).key),
+					strings.Join(rows, ","),
+					trades[len(trades)-1].Time.UnixNano()),
+			},
+		}, nil
+	}
+}
+
+func (k Kraken) assetPair(e ExchangeMock) string {
+	pair := k.pair(e.Symbol)
+	body := `%s: {
+	"altname": %s,
+	"wsname": %s,
+	"aclass_base": "currency",
+	"base": %s,
+	"aclass_quote": "currency",
+	"quote": %s,
+	"lot": "unit",
+	"cost_decimals": 5,
+	"pair_decimals": 5,
+	"lot_decimals": 8,
+	"lot_multiplier": 1,
+	"fee_volume_currency": "ZUSD",
+	"margin_call": 80,
+	"margin_stop": 40,
+	"ordermin": "0.01",
+	"costmin": "0.5",
+	"tick_size": "0.00001",
+	"status": "online"
 }`
+	return fmt.Sprintf(body,
+		jsonString(pair.key),
+		jsonString(pair.altname),
+		jsonString(pair.wsname),
+		jsonString(pair.base.id),
+		jsonString(pair.quote.id))
+}
 
+// buildAssetPairs builds asset pairs of all symbols.
+func (k Kraken) buildAssetPairs(e []ExchangeMock) (*smocker.Mock, error) {
+	pairs := make([]string, 0, len(e))
+	for _, ex := range e {
+		pairs = append(pairs, k.assetPair(ex))
+	}
 	return &smocker.Mock{
 		Request: smocker.MockRequest{
 			Method: smocker.ShouldEqual("GET"),
-			Path:   smocker.ShouldEqual("/0/public/Ticker"),
+			Path:   smocker.ShouldEqual("/0/public/AssetPairs"),
+		},
+		Response: &smocker.MockResponse{
+			Status: http.StatusOK,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf(`{"error": [], "result": {%s}}`, strings.Join(pairs, ",")),
+		},
+	}, nil
+}
+
+func (k Kraken) buildAssetPairsForOne(e ExchangeMock) (*smocker.Mock, error) {
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual("/0/public/AssetPairs"),
 			QueryParams: map[string]smocker.StringMatcherSlice{
 				"pair": []smocker.StringMatcher{
-					smocker.ShouldEqual(symbol),
+					k.pairMatcher(e.Symbol),
 				},
 			},
 		},
@@ -115,7 +334,141 @@ func (k Kraken) build(e ExchangeMock) (*smocker.Mock, error) {
 					"application/json",
 				},
 			},
-			Body: fmt.Sprintf(body, symbol, e.Ask, e.Bid, e.Price, e.Volume),
+			Body: fmt.Sprintf(`{"error": [], "result": {%s}}`, k.assetPair(e)),
 		},
 	}, nil
 }
+
+// buildAssets builds assets of all symbols.
+func (k Kraken) buildAssets(e []ExchangeMock) (*smocker.Mock, error) {
+	body := `%s: {
+	"aclass": "currency",
+	"altname": %s,
+	"decimals": 10,
+	"display_decimals": 5,
+	"status": "enabled"
+}`
+	seen := map[string]bool{}
+	assets := make([]string, 0, 2*len(e))
+	for _, ex := range e {
+		pair := k.pair(ex.Symbol)
+		for _, asset := range []krakenAsset{pair.base, pair.quote} {
+			if seen[asset.id] {
+				continue
+			}
+			seen[asset.id] = true
+			assets = append(assets, fmt.Sprintf(body, jsonString(asset.id), jsonString(asset.altname)))
+		}
+	}
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual("/0/public/Assets"),
+		},
+		Response: &smocker.MockResponse{
+			Status: http.StatusOK,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf(`{"error": [], "result": {%s}}`, strings.Join(assets, ",")),
+		},
+	}, nil
+}
+
+// krakenAsset is an asset as named by Kraken.
+type krakenAsset struct {
+	// id is used in pair names and as the key of the Assets endpoint, e.g. `XXBT`.
+	id string
+	// altname is the short name, e.g. `XBT`.
+	altname string
+}
+
+// legacy reports whether the asset has the X (crypto) or Z (fiat) prefix.
+func (a krakenAsset) legacy() bool {
+	return a.id != a.altname
+}
+
+// krakenAssets lists assets with names different from common ones, by common name.
+// Other assets are named as usual.
+var krakenAssets = map[string]krakenAsset{
+	"BTC":  {id: "XXBT", altname: "XBT"},
+	"DOGE": {id: "XXDG", altname: "XDG"},
+	"ETC":  {id: "XETC", altname: "ETC"},
+	"ETH":  {id: "XETH", altname: "ETH"},
+	"LTC":  {id: "XLTC", altname: "LTC"},
+	"MLN":  {id: "XMLN", altname: "MLN"},
+	"REP":  {id: "XREP", altname: "REP"},
+	"XLM":  {id: "XXLM", altname: "XLM"},
+	"XMR":  {id: "XXMR", altname: "XMR"},
+	"XRP":  {id: "XXRP", altname: "XRP"},
+	"ZEC":  {id: "XZEC", altname: "ZEC"},
+	"AUD":  {id: "ZAUD", altname: "AUD"},
+	"CAD":  {id: "ZCAD", altname: "CAD"},
+	"EUR":  {id: "ZEUR", altname: "EUR"},
+	"GBP":  {id: "ZGBP", altname: "GBP"},
+	"JPY":  {id: "ZJPY", altname: "JPY"},
+	"USD":  {id: "ZUSD", altname: "USD"},
+}
+
+// asset returns the Kraken asset for the common name, Kraken names (e.g. `XBT`
+// or `XXBT`) are accepted too.
+func (k Kraken) asset(name string) krakenAsset {
+	if a, ok := krakenAssets[name]; ok {
+		return a
+	}
+	for _, a := range krakenAssets {
+		if name == a.id || name == a.altname {
+			return a
+		}
+	}
+	return krakenAsset{id: name, altname: name}
+}
+
+// krakenPair is an asset pair as named by Kraken.
+type krakenPair struct {
+	base  krakenAsset
+	quote krakenAsset
+	// key is used in responses, e.g. `XETHXXBT`.
+	key string
+	// altname is e.g. `ETHXBT`.
+	altname string
+	// wsname is e.g. `ETH/XBT`.
+	wsname string
+}
+
+// krakenPairKeys lists keys of pairs of legacy assets that are not keyed by
+// asset ids, by wsname.
+var krakenPairKeys = map[string]string{
+	"XDG/EUR": "XDGEUR",
+	"XDG/USD": "XDGUSD",
+	"XDG/XBT": "XDGXBT",
+}
+
+// pair returns names of the symbol on Kraken. Pairs of legacy assets are keyed
+// by their ids, e.g. ETH/BTC is `XETHXXBT`, unless listed in krakenPairKeys,
+// and other pairs by altnames.
+func (k Kraken) pair(s Symbol) krakenPair {
+	p := krakenPair{base: k.asset(s.Base), quote: k.asset(s.Quote)}
+	p.altname = p.base.altname + p.quote.altname
+	p.wsname = p.base.altname + "/" + p.quote.altname
+	p.key = p.altname
+	if key, ok := krakenPairKeys[p.wsname]; ok {
+		p.key = key
+	} else if p.base.legacy() && p.quote.legacy() {
+		p.key = p.base.id + p.quote.id
+	}
+	return p
+}
+
+// pairMatcher matches every name of the symbol clients may use to request it:
+// the key, the altname, the wsname and the common names with or without slash.
+func (k Kraken) pairMatcher(s Symbol) smocker.StringMatcher {
+	pair := k.pair(s)
+	names := []string{pair.key, pair.altname, pair.wsname, s.Format("%s%s"), s.Format("%s/%s")}
+	for i, name := range names {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	return smocker.ShouldMatch(fmt.Sprintf("^(%s)$", strings.Join(names, "|")))
+}
@@ -2,6 +2,7 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -9,7 +10,32 @@ import (
 type KuCoin struct{}
 
 func (k KuCoin) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
-	return CombineMocks(e, k.build)
+	mocks, err := CombineMocks(e, k.build)
+	if err != nil {
+		return nil, err
+	}
+	// Deeper order books are auxiliary, clients usually request one depth.
+	for i, f := range []MockableFunc{
+		k.buildLevel2("/api/v1/market/orderbook/level2_20", 20),
+		k.buildLevel2("/api/v1/market/orderbook/level2_100", 100),
+		k.buildLevel2("/api/v3/market/orderbook/level2", 0),
+	} {
+		level2, err := CombineMocks(e, f)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			auxiliary(level2)
+		}
+		mocks = append(mocks, level2...)
+	}
+	return mocks, nil
+}
+
+func (k KuCoin) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusBadRequest, "400100", "Unsupported trading pair.")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"code":%s,"msg":%s}`, jsonString(ee.Code), jsonString(ee.Message)))
+	return nil
 }
 
 func (k KuCoin) build(e ExchangeMock) (*smocker.Mock, error) {
@@ -45,3 +71,51 @@ func (k KuCoin) build(e ExchangeMock) (*smocker.Mock, error) {
 		},
 	}, nil
 }
+
+// buildLevel2 builds the order book served by the path, with at most depth
+// levels on each side. Zero depth serves the full order book.
+func (k KuCoin) buildLevel2(path string, depth int) MockableFunc {
+	return func(e ExchangeMock) (*smocker.Mock, error) {
+		if e.OrderBook == nil {
+			return nil, nil
+		}
+		book := e.OrderBook.sorted()
+		if depth > 0 {
+			book = book.limit(depth)
+		}
+		body := `{
+	"code": "200000",
+	"data": {
+		"time": %d,
+		"sequence": "%d",
+		"bids": %s,
+		"asks": %s
+	}
+}`
+
+		return &smocker.Mock{
+			Request: smocker.MockRequest{
+				Method: smocker.ShouldEqual("GET"),
+				Path:   smocker.ShouldEqual(path),
+				QueryParams: map[string]smocker.StringMatcherSlice{
+					"symbol": []smocker.StringMatcher{
+						smocker.ShouldEqual(e.Symbol.Format("%s-%s")),
+					},
+				},
+			},
+			Response: &smocker.MockResponse{
+				Status: e.StatusCode,
+				Headers: map[string]smocker.StringSlice{
+					"Content-Type": []string{
+						"application/json",
+					},
+				},
+				Body: fmt.Sprintf(body,
+					e.Timestamp.UnixMilli(),
+					e.Timestamp.UnixMilli(),
+					formatLevels(book.Bids, `["%f","%f"]`),
+					formatLevels(book.Asks, `["%f","%f"]`)),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,86 @@
+package origin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// LatencyProfile is a named range of response delays, see ExchangeMock.WithLatency.
+type LatencyProfile string
+
+const (
+	// LatencyFast delays responses by 10-50ms.
+	LatencyFast LatencyProfile = "fast"
+	// LatencyNormal delays responses by 50-250ms.
+	LatencyNormal LatencyProfile = "normal"
+	// LatencySlow delays responses by 1-3s.
+	LatencySlow LatencyProfile = "slow"
+	// LatencyTimeout delays responses by 30s, longer than usual client timeouts.
+	LatencyTimeout LatencyProfile = "timeout"
+)
+
+var latencyProfiles = map[LatencyProfile]smocker.Delay{
+	LatencyFast:    {Min: 10 * time.Millisecond, Max: 50 * time.Millisecond},
+	LatencyNormal:  {Min: 50 * time.Millisecond, Max: 250 * time.Millisecond},
+	LatencySlow:    {Min: time.Second, Max: 3 * time.Second},
+	LatencyTimeout: {Min: 30 * time.Second, Max: 30 * time.Second},
+}
+
+// WithDelay delays every response of the exchange by a random duration between
+// min and max.
+func (e *ExchangeMock) WithDelay(min, max time.Duration) *ExchangeMock {
+	e.Delay = &smocker.Delay{Min: min, Max: max}
+	e.Latency = ""
+	return e
+}
+
+// WithLatency delays every response of the exchange as described by the profile.
+func (e *ExchangeMock) WithLatency(profile LatencyProfile) *ExchangeMock {
+	e.Latency = profile
+	e.Delay = nil
+	return e
+}
+
+// delay returns the delay of responses, nil if they are not delayed.
+func (e ExchangeMock) delay() (*smocker.Delay, error) {
+	if e.Latency == "" {
+		return e.Delay, nil
+	}
+	d, ok := latencyProfiles[e.Latency]
+	if !ok {
+		return nil, fmt.Errorf("unknown latency profile %s", e.Latency)
+	}
+	return &d, nil
+}
+
+// applyDelay delays the response of the mock built for the exchange mock. Delays
+// of dynamic responses are added to the rendered response.
+func applyDelay(e ExchangeMock, m *smocker.Mock) error {
+	d, err := e.delay()
+	if err != nil || d == nil {
+		return err
+	}
+	switch {
+	case m.Response != nil:
+		m.Response.Delay = *d
+	case m.DynamicResponse != nil:
+		switch m.DynamicResponse.Engine {
+		case smocker.GoTemplateJSONEngine:
+			script := strings.TrimLeft(m.DynamicResponse.Script, " \t\r\n")
+			if !strings.HasPrefix(script, "{") {
+				return fmt.Errorf("failed to add delay to dynamic response: script is not a JSON object")
+			}
+			m.DynamicResponse.Script = fmt.Sprintf(`{"delay": {"min": %d, "max": %d}, %s`, d.Min, d.Max, script[1:])
+		case smocker.GoTemplateEngine, smocker.GoTemplateYAMLEngine:
+			m.DynamicResponse.Script = fmt.Sprintf("delay:\n  min: %s\n  max: %s\n%s", d.Min, d.Max, m.DynamicResponse.Script)
+		default:
+			return fmt.Errorf("delay is not supported for %s dynamic responses", m.DynamicResponse.Engine)
+		}
+	default:
+		return fmt.Errorf("delay is not supported for proxy responses")
+	}
+	return nil
+}
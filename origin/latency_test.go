@@ -0,0 +1,50 @@
+package origin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func TestLatencyProfile(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{
+		"binance", "bitfinex", "bitstamp", "coinbase", "gemini", "hitbtc",
+		"huobi", "kraken", "kucoin", "okex", "poloniex", "upbit",
+	} {
+		for profile, delay := range latencyProfiles {
+			ex := NewExchange(name).
+				WithSymbol("ETH/BTC").
+				WithPrice(1).
+				WithOrderBook(OrderBook{Bids: []OrderBookLevel{NewOrderBookLevel(1, 1)}}).
+				WithCandles(time.Minute, NewCandle(start, 1, 1, 1, 1, 1)).
+				WithTrades(NewTrade(start, 1, 1, TradeBuy)).
+				WithLatency(profile)
+			mocks, err := BuildMocksForExchanges(name, []ExchangeMock{*ex})
+			require.NoError(t, err, "%s: %s", name, profile)
+			require.NotEmpty(t, mocks)
+			for _, m := range mocks {
+				require.NotNil(t, m.Response, "%s: %s", name, m.Request.Path.Value)
+				require.Equal(t, delay, m.Response.Delay, "%s: %s", name, m.Request.Path.Value)
+			}
+		}
+	}
+
+	// Dynamic responses get the delay in the rendered response.
+	ex := NewExchange("ethrpc").WithCustom("blockNumber", 100).WithLatency(LatencySlow)
+	mocks, err := BuildMocksForExchanges("ethrpc", []ExchangeMock{*ex})
+	require.NoError(t, err)
+	require.NotEmpty(t, mocks)
+	for _, m := range mocks {
+		require.NotNil(t, m.DynamicResponse)
+		require.Equal(t, smocker.GoTemplateJSONEngine, m.DynamicResponse.Engine)
+		require.Contains(t, m.DynamicResponse.Script, `{"delay": {"min": 1000000000, "max": 3000000000}, `)
+	}
+
+	ex = NewExchange("kraken").WithSymbol("ETH/BTC").WithLatency("unknown")
+	_, err = BuildMocksForExchanges("kraken", []ExchangeMock{*ex})
+	require.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package origin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// mockedLimits are the limits mocked for endpoints with a limit query parameter.
+// Requests with other limits lower than the number of items match no mock, so
+// Smocker fails them with StatusNoMockFound instead of serving a wrong number of
+// items.
+var mockedLimits = []int{1, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+// limitAll is the limit of mocks serving all items, they match requests with
+// limits not lower than the number of items.
+const limitAll = -1
+
+// limitItems returns the number of last items out of n served for the limit,
+// false if no mock is needed for the limit. Limit 0 serves the last def items,
+// def 0 serves all items.
+func limitItems(n, limit, def int) (int, bool) {
+	switch {
+	case limit == 0:
+		if def > 0 && def < n {
+			return def, true
+		}
+		return n, true
+	case limit == limitAll:
+		return n, true
+	case limit >= n:
+		return 0, false
+	default:
+		return limit, true
+	}
+}
+
+// limitMatchers returns matchers of the limit query parameter for a mock of n
+// items. Smocker matches query parameters value by value, so the mock for limit
+// 0 has no matcher and serves only requests without the parameter.
+func limitMatchers(limit, n int) smocker.StringMatcherSlice {
+	switch limit {
+	case 0:
+		return smocker.StringMatcherSlice{}
+	case limitAll:
+		return smocker.StringMatcherSlice{smocker.ShouldMatch(atLeastPattern(n))}
+	default:
+		return smocker.StringMatcherSlice{smocker.ShouldEqual(strconv.Itoa(limit))}
+	}
+}
+
+// atLeastPattern returns a pattern matching decimal integers not lower than n.
+func atLeastPattern(n int) string {
+	if n <= 0 {
+		return `^\d+$`
+	}
+	s := strconv.Itoa(n)
+	// Numbers with more digits than n.
+	alts := []string{fmt.Sprintf(`[1-9]\d{%d,}`, len(s))}
+	// Numbers with as many digits as n, greater than n at the i-th digit.
+	for i := 0; i < len(s); i++ {
+		if s[i] == '9' {
+			continue
+		}
+		alt := fmt.Sprintf(`%s[%c-9]`, s[:i], s[i]+1)
+		if rest := len(s) - i - 1; rest > 0 {
+			alt += fmt.Sprintf(`\d{%d}`, rest)
+		}
+		alts = append(alts, alt)
+	}
+	alts = append(alts, s)
+	return fmt.Sprintf("^0*(%s)$", strings.Join(alts, "|"))
+}
+
+// buildLimitMocks builds mocks with f for each limit: for limit 0, which serves
+// requests without a limit, and with limits, for mockedLimits and limitAll too.
+// Mocks of limits must use limitMatchers, and they are auxiliary.
+func buildLimitMocks(e []ExchangeMock, limits bool, f func(limit int) MockableFunc) ([]*smocker.Mock, error) {
+	all := []int{0}
+	if limits {
+		all = append(append(all, mockedLimits...), limitAll)
+	}
+	var mocks []*smocker.Mock
+	for _, limit := range all {
+		m, err := CombineMocks(e, f(limit))
+		if err != nil {
+			return nil, err
+		}
+		if limit != 0 {
+			auxiliary(m)
+		}
+		mocks = append(mocks, m...)
+	}
+	return mocks, nil
+}
@@ -0,0 +1,47 @@
+package origin
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtLeastPattern(t *testing.T) {
+	for _, n := range []int{0, 1, 9, 10, 19, 99, 100, 500, 909, 1000} {
+		re := regexp.MustCompile(atLeastPattern(n))
+		for i := 0; i <= 2000; i++ {
+			require.Equal(t, i >= n, re.MatchString(strconv.Itoa(i)), "n=%d i=%d", n, i)
+		}
+		require.False(t, re.MatchString(""))
+		require.False(t, re.MatchString("abc"))
+	}
+}
+
+func TestCandleMocksCount(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var candles []Candle
+	for i := 0; i < 600; i++ {
+		candles = append(candles, NewCandle(start.Add(time.Duration(i)*time.Minute), 1, 1, 1, 1, 1))
+	}
+	e := []ExchangeMock{*NewExchange("binance").WithSymbol("ETH/BTC").WithCandles(time.Minute, candles...)}
+	mocks, err := buildCandleMocks(e, binanceIntervals[:1], true, Binance{}.buildKlines)
+	require.NoError(t, err)
+	// Default, mocked limits up to 500 and all candles.
+	require.Len(t, mocks, 10)
+}
+
+func TestTradeMocksCount(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var trades []Trade
+	for i := 0; i < 1000; i++ {
+		trades = append(trades, NewTrade(start.Add(time.Duration(i)*time.Second), 1, 1, TradeBuy))
+	}
+	e := []ExchangeMock{*NewExchange("kraken").WithSymbol("ETH/BTC").WithTrades(trades...)}
+	mocks, err := buildLimitMocks(e, true, Kraken{}.buildTrades)
+	require.NoError(t, err)
+	// Default, mocked limits up to 500 and all trades.
+	require.Len(t, mocks, 10)
+}
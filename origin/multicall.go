@@ -1,10 +1,14 @@
 package origin
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/infestor/smocker"
 )
 
 var multicallMethod = abi.MustParseMethod(`
@@ -33,3 +37,29 @@ func encodeMultiCallResponse(blockNumber int64, data []any) ([]byte, error) {
 	respEncoded, err := abi.EncodeValues(multicallMethod.Outputs(), big.NewInt(blockNumber).Uint64(), data)
 	return respEncoded, err
 }
+
+// multiCallMock builds a mock of the multicall of calls, which responds with
+// data returned by the calls at the block number.
+func multiCallMock(e ExchangeMock, blockNumber int64, calls []MultiCall, data []any) (*smocker.Mock, error) {
+	args, err := encodeMultiCallArgs(calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode multicall arguments: %w", err)
+	}
+	resp, err := encodeMultiCallResponse(blockNumber, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode multicall response: %w", err)
+	}
+
+	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
+
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("POST"),
+			Path:   smocker.ShouldEqual("/"),
+			Body: &smocker.BodyMatcher{
+				BodyString: &m,
+			},
+		},
+		DynamicResponse: rpcResult(e.StatusCode, hexutil.BytesToHex(resp)),
+	}, nil
+}
@@ -2,6 +2,7 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -12,6 +13,18 @@ func (o Okex) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	return CombineMocks(e, o.build)
 }
 
+func (o Okex) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusBadRequest, "30032", "The currency pair is suspended for trading")
+	code, err := ee.numericCode()
+	if err != nil {
+		return err
+	}
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(
+		`{"code":%d,"message":%s,"error_code":"%d","error_message":%s}`,
+		code, jsonString(ee.Message), code, jsonString(ee.Message)))
+	return nil
+}
+
 func (o Okex) build(e ExchangeMock) (*smocker.Mock, error) {
 	symbol := e.Symbol.Format("%s-%s")
 	body := `{
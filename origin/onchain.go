@@ -0,0 +1,159 @@
+package origin
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// On-chain origins read their configuration from ExchangeMock.Custom: the
+// `blockNumber` and lists of FunctionData keyed by the function name. Contracts
+// below fill them with correctly typed values, and the helpers validate them at
+// build time, so misconfigured mocks fail with an error instead of a panic.
+
+// Contract provides function data for on-chain origins, see ExchangeMock.WithContract.
+type Contract interface {
+	// FunctionData returns function data keyed by the function name,
+	// e.g. `slot0` for UniswapV3Pool.
+	FunctionData() map[string][]FunctionData
+}
+
+// WithBlockNumber sets the block number reported by on-chain origins.
+func (e *ExchangeMock) WithBlockNumber(blockNumber int) *ExchangeMock {
+	e.Custom["blockNumber"] = blockNumber
+	return e
+}
+
+// WithContract appends function data of contracts to the data already set with
+// WithFunctionData or previous WithContract calls.
+func (e *ExchangeMock) WithContract(contracts ...Contract) *ExchangeMock {
+	for _, c := range contracts {
+		for funcName, funcData := range c.FunctionData() {
+			existing, _ := e.Custom[funcName].([]FunctionData)
+			e.Custom[funcName] = append(existing, funcData...)
+		}
+	}
+	return e
+}
+
+// blockNumber returns the block number, which is required by all on-chain origins.
+func (e ExchangeMock) blockNumber() (int64, error) {
+	switch v := e.Custom["blockNumber"].(type) {
+	case nil:
+		return 0, fmt.Errorf("not found block number")
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("block number %d is out of range", v)
+		}
+		return int64(v), nil
+	case *big.Int:
+		if v == nil || !v.IsInt64() {
+			return 0, fmt.Errorf("block number %v is out of range", v)
+		}
+		return v.Int64(), nil
+	case float64:
+		// Numbers decoded from YAML or JSON scenarios.
+		if v != math.Trunc(v) || v < 0 || v > math.MaxInt64 {
+			return 0, fmt.Errorf("block number %v is not an integer", v)
+		}
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("block number must be an integer, got %T", v)
+	}
+}
+
+// functionData returns function data set for the function, or nil if there is none.
+func (e ExchangeMock) functionData(funcName string) ([]FunctionData, error) {
+	switch v := e.Custom[funcName].(type) {
+	case nil:
+		return nil, nil
+	case []FunctionData:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("function data for %s must be []FunctionData, got %T", funcName, v)
+	}
+}
+
+// argAt returns the i-th argument of the function data, name describes it in errors.
+func argAt[T any](f FunctionData, i int, name string) (T, error) {
+	return valueAt[T](f.Args, i, "argument", name)
+}
+
+// returnAt returns the i-th return value of the function data, name describes it in errors.
+func returnAt[T any](f FunctionData, i int, name string) (T, error) {
+	return valueAt[T](f.Return, i, "return value", name)
+}
+
+func valueAt[T any](values []any, i int, kind, name string) (T, error) {
+	var zero T
+	if i >= len(values) {
+		return zero, fmt.Errorf("missing %s #%d (%s)", kind, i, name)
+	}
+	v, ok := values[i].(T)
+	if !ok {
+		return zero, fmt.Errorf("%s #%d (%s) must be %T, got %T", kind, i, name, zero, values[i])
+	}
+	if b, ok := any(v).(*big.Int); ok && b == nil {
+		return zero, fmt.Errorf("%s #%d (%s) must not be nil", kind, i, name)
+	}
+	return v, nil
+}
+
+// buildUint256Calls builds the mock of calls to the function that returns a single
+// uint256, encodeArgs encodes the call of the function data.
+func buildUint256Calls(
+	e ExchangeMock,
+	funcName string,
+	encodeArgs func(f FunctionData) ([]byte, error),
+) (*smocker.Mock, error) {
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
+	}
+	funcData, err := e.functionData(funcName)
+	if err != nil {
+		return nil, err
+	}
+	if len(funcData) < 1 {
+		return nil, fmt.Errorf("not found function data for %s", funcName)
+	}
+
+	var calls []MultiCall
+	var data []any
+	for i := 0; i < len(funcData); i++ {
+		args, err := encodeArgs(funcData[i])
+		if err != nil {
+			return nil, functionDataError(funcName, i, err)
+		}
+		value, err := returnAt[*big.Int](funcData[i], 0, "value")
+		if err != nil {
+			return nil, functionDataError(funcName, i, err)
+		}
+		calls = append(calls, MultiCall{
+			Target: funcData[i].Address,
+			Data:   args,
+		})
+		data = append(data, types.Bytes(value.Bytes()).PadLeft(32))
+	}
+	return multiCallMock(e, blockNumber, calls, data)
+}
+
+func zeroIfNil(b *big.Int) *big.Int {
+	if b == nil {
+		return big.NewInt(0)
+	}
+	return b
+}
+
+// functionDataError describes the invalid function data item.
+func functionDataError(funcName string, i int, err error) error {
+	return fmt.Errorf("invalid function data %s[%d]: %w", funcName, i, err)
+}
@@ -0,0 +1,78 @@
+package origin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OrderBookLevel is a price level of an order book.
+type OrderBookLevel struct {
+	Price  Decimal
+	Amount Decimal
+}
+
+// NewOrderBookLevel returns the level with given price and amount.
+func NewOrderBookLevel(price, amount float64) OrderBookLevel {
+	return OrderBookLevel{Price: NewDecimal(price), Amount: NewDecimal(amount)}
+}
+
+// OrderBook is rendered by depth endpoints of origins, see ExchangeMock.WithOrderBook.
+// Levels may be in any order, bids are sorted by descending and asks by ascending
+// price when rendered.
+type OrderBook struct {
+	Bids []OrderBookLevel
+	Asks []OrderBookLevel
+}
+
+// WithOrderBook sets the order book served by depth endpoints of the exchange.
+// Depth endpoints are mocked only for exchange mocks with an order book.
+func (e *ExchangeMock) WithOrderBook(book OrderBook) *ExchangeMock {
+	e.OrderBook = &book
+	return e
+}
+
+// sorted returns a copy of the order book with sorted levels.
+func (b OrderBook) sorted() OrderBook {
+	bids := append([]OrderBookLevel{}, b.Bids...)
+	asks := append([]OrderBookLevel{}, b.Asks...)
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Price.Rat().Cmp(bids[j].Price.Rat()) > 0 })
+	sort.SliceStable(asks, func(i, j int) bool { return asks[i].Price.Rat().Cmp(asks[j].Price.Rat()) < 0 })
+	return OrderBook{Bids: bids, Asks: asks}
+}
+
+// limit returns the order book with at most n levels on each side.
+func (b OrderBook) limit(n int) OrderBook {
+	if len(b.Bids) > n {
+		b.Bids = b.Bids[:n]
+	}
+	if len(b.Asks) > n {
+		b.Asks = b.Asks[:n]
+	}
+	return b
+}
+
+// withDecimals returns a copy of the order book with values formatted with n decimals.
+func (b OrderBook) withDecimals(n int) OrderBook {
+	res := OrderBook{
+		Bids: make([]OrderBookLevel, len(b.Bids)),
+		Asks: make([]OrderBookLevel, len(b.Asks)),
+	}
+	for i, l := range b.Bids {
+		res.Bids[i] = OrderBookLevel{Price: l.Price.WithDecimals(n), Amount: l.Amount.WithDecimals(n)}
+	}
+	for i, l := range b.Asks {
+		res.Asks[i] = OrderBookLevel{Price: l.Price.WithDecimals(n), Amount: l.Amount.WithDecimals(n)}
+	}
+	return res
+}
+
+// formatLevels renders levels as a JSON array, each level is formatted with
+// its price and amount, e.g. `["%.8f","%.8f"]`.
+func formatLevels(levels []OrderBookLevel, format string) string {
+	res := make([]string, 0, len(levels))
+	for _, l := range levels {
+		res = append(res, fmt.Sprintf(format, l.Price, l.Amount))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(res, ","))
+}
@@ -0,0 +1,174 @@
+package origin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// Poloniex mocks public ticker endpoints:
+//   - `/markets/{symbol}/ticker24h` for a single market,
+//   - `/markets/ticker24h` for all markets,
+//   - legacy `/public?command=returnTicker` for all markets.
+//
+// All-markets responses contain every mocked market, see AggregateMocks.
+
+type Poloniex struct{}
+
+func (p Poloniex) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
+	mocks, err := CombineMocks(e, p.buildForOne)
+	if err != nil {
+		return nil, err
+	}
+	tickers, err := AggregateMocks(e, p.buildTickers)
+	if err != nil {
+		return nil, err
+	}
+	returnTicker, err := AggregateMocks(e, p.buildReturnTicker)
+	if err != nil {
+		return nil, err
+	}
+	mocks = append(mocks, auxiliary(tickers)...)
+	return append(mocks, auxiliary(returnTicker)...), nil
+}
+
+func (p Poloniex) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusBadRequest, "21701", "Invalid currency pair")
+	code, err := ee.numericCode()
+	if err != nil {
+		return err
+	}
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"code":%d,"message":%s}`, code, jsonString(ee.Message)))
+	return nil
+}
+
+func (p Poloniex) ticker(e ExchangeMock) string {
+	body := `{
+	"symbol": "%s",
+	"open": "%f",
+	"low": "%f",
+	"high": "%f",
+	"close": "%f",
+	"quantity": "%f",
+	"amount": "%f",
+	"tradeCount": 1709,
+	"startTime": %d,
+	"closeTime": %d,
+	"displayName": "%s",
+	"dailyChange": "0.00",
+	"bid": "%f",
+	"bidQuantity": "0.5",
+	"ask": "%f",
+	"askQuantity": "0.5",
+	"ts": %d,
+	"markPrice": "%f"
+}`
+	ts := e.Timestamp.UnixMilli()
+	return fmt.Sprintf(body,
+		e.Symbol.Format("%s_%s"),
+		e.Price,
+		e.Price,
+		e.Price,
+		e.Price,
+		e.Volume,
+		e.Volume.Mul(e.Price),
+		ts-24*60*60*1000,
+		ts,
+		e.Symbol.String(),
+		e.Bid,
+		e.Ask,
+		ts,
+		e.Price)
+}
+
+func (p Poloniex) buildForOne(e ExchangeMock) (*smocker.Mock, error) {
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual(fmt.Sprintf("/markets/%s/ticker24h", e.Symbol.Format("%s_%s"))),
+		},
+		Response: &smocker.MockResponse{
+			Status: e.StatusCode,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: p.ticker(e),
+		},
+	}, nil
+}
+
+func (p Poloniex) buildTickers(e []ExchangeMock) (*smocker.Mock, error) {
+	tickers := make([]string, 0, len(e))
+	for _, ex := range e {
+		tickers = append(tickers, p.ticker(ex))
+	}
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual("/markets/ticker24h"),
+		},
+		Response: &smocker.MockResponse{
+			Status: http.StatusOK,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf("[%s]", strings.Join(tickers, ",")),
+		},
+	}, nil
+}
+
+// buildReturnTicker builds the legacy all-markets ticker. Legacy markets are
+// named `QUOTE_BASE`, so `baseVolume` is the volume in the quote asset.
+func (p Poloniex) buildReturnTicker(e []ExchangeMock) (*smocker.Mock, error) {
+	body := `"%s": {
+	"id": %d,
+	"last": "%f",
+	"lowestAsk": "%f",
+	"highestBid": "%f",
+	"percentChange": "0.00000000",
+	"baseVolume": "%f",
+	"quoteVolume": "%f",
+	"isFrozen": "0",
+	"high24hr": "%f",
+	"low24hr": "%f"
+}`
+	tickers := make([]string, 0, len(e))
+	for i, ex := range e {
+		tickers = append(tickers, fmt.Sprintf(body,
+			ex.Symbol.Format("%[2]s_%[1]s"),
+			i+1,
+			ex.Price,
+			ex.Ask,
+			ex.Bid,
+			ex.Volume.Mul(ex.Price),
+			ex.Volume,
+			ex.Price,
+			ex.Price))
+	}
+	return &smocker.Mock{
+		Request: smocker.MockRequest{
+			Method: smocker.ShouldEqual("GET"),
+			Path:   smocker.ShouldEqual("/public"),
+			QueryParams: map[string]smocker.StringMatcherSlice{
+				"command": []smocker.StringMatcher{
+					smocker.ShouldEqual("returnTicker"),
+				},
+			},
+		},
+		Response: &smocker.MockResponse{
+			Status: http.StatusOK,
+			Headers: map[string]smocker.StringSlice{
+				"Content-Type": []string{
+					"application/json",
+				},
+			},
+			Body: fmt.Sprintf("{%s}", strings.Join(tickers, ",")),
+		},
+	}, nil
+}
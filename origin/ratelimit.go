@@ -0,0 +1,128 @@
+package origin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// RateLimit makes an origin respond like its rate limiter after a number of
+// successful calls, see ExchangeMock.WithRateLimit.
+type RateLimit struct {
+	// After is the number of successful calls to each endpoint before the limit is hit.
+	After uint
+	// StatusCode of rate limited responses, 429 by default. Binance responds with
+	// 418 to clients that keep calling after being limited.
+	StatusCode int
+	// RetryAfter is sent in the Retry-After header, 60 seconds by default.
+	RetryAfter time.Duration
+}
+
+// RateLimitMockable is implemented by origins that respond with their own rate limit payloads.
+type RateLimitMockable interface {
+	// BuildRateLimitResponse updates the mock built for the n-th call, starting at 1,
+	// to an endpoint. Mocks for calls after RateLimit.After already respond with the
+	// default rate limit response.
+	BuildRateLimitResponse(e ExchangeMock, m *smocker.Mock, n uint) error
+}
+
+// WithRateLimit makes every endpoint of the exchange respond successfully rl.After
+// times, and with the rate limit error of the origin afterwards.
+func (e *ExchangeMock) WithRateLimit(rl RateLimit) *ExchangeMock {
+	e.RateLimit = &rl
+	return e
+}
+
+func (rl RateLimit) withDefaults() RateLimit {
+	if rl.StatusCode == 0 {
+		rl.StatusCode = http.StatusTooManyRequests
+	}
+	if rl.RetryAfter == 0 {
+		rl.RetryAfter = time.Minute
+	}
+	return rl
+}
+
+// limited reports whether the n-th call is rate limited.
+func (rl RateLimit) limited(n uint) bool {
+	return n > rl.After
+}
+
+// applyRateLimit returns mocks for successive calls to the endpoint of the mock:
+// one mock for each successful call and the rate limited mock for the rest.
+// Smocker prefers mocks added later, so they are returned in reverse call order.
+// Mocks of calls after the first one are auxiliary, clients may not reach the limit.
+func applyRateLimit(e ExchangeMock, m *smocker.Mock) ([]*smocker.Mock, error) {
+	if e.RateLimit == nil {
+		return []*smocker.Mock{m}, nil
+	}
+	ex, ok := lookupExchange(e.Name)
+	if !ok {
+		return nil, fmt.Errorf("failed to find exchange name %s", e.Name)
+	}
+	rm, _ := ex.(RateLimitMockable)
+	rl := e.RateLimit.withDefaults()
+	mocks := make([]*smocker.Mock, 0, rl.After+1)
+	for n := rl.After + 1; n > 0; n-- {
+		c := cloneMock(m)
+		if rl.limited(n) {
+			setRateLimitResponse(c, rl)
+		} else {
+			c.Context = &smocker.MockContext{Times: 1}
+		}
+		if rm != nil {
+			if err := rm.BuildRateLimitResponse(e, c, n); err != nil {
+				return nil, fmt.Errorf("failed to build rate limit response: %w", err)
+			}
+		}
+		if n > 1 {
+			auxiliary([]*smocker.Mock{c})
+		}
+		mocks = append(mocks, c)
+	}
+	return mocks, nil
+}
+
+// setRateLimitResponse replaces the response of the mock with an empty rate limit response.
+func setRateLimitResponse(m *smocker.Mock, rl RateLimit) {
+	m.DynamicResponse = nil
+	m.Proxy = nil
+	m.Context = nil
+	m.Response = &smocker.MockResponse{
+		Status: rl.StatusCode,
+		Headers: map[string]smocker.StringSlice{
+			"Retry-After": []string{
+				strconv.Itoa(int(rl.RetryAfter / time.Second)),
+			},
+		},
+	}
+}
+
+// cloneMock returns a copy of the mock that can be changed without affecting the original.
+func cloneMock(m *smocker.Mock) *smocker.Mock {
+	c := *m
+	if m.Response != nil {
+		r := *m.Response
+		r.Headers = make(smocker.MapStringSlice, len(m.Response.Headers))
+		for k, v := range m.Response.Headers {
+			r.Headers[k] = append(smocker.StringSlice{}, v...)
+		}
+		c.Response = &r
+	}
+	if m.DynamicResponse != nil {
+		d := *m.DynamicResponse
+		c.DynamicResponse = &d
+	}
+	if m.Proxy != nil {
+		p := *m.Proxy
+		c.Proxy = &p
+	}
+	if m.Context != nil {
+		ctx := *m.Context
+		c.Context = &ctx
+	}
+	return &c
+}
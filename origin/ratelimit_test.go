@@ -0,0 +1,20 @@
+package origin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+func TestBinanceRateLimitWithoutStaticResponse(t *testing.T) {
+	e := NewExchange("binance").WithSymbol("ETH/BTC").WithRateLimit(RateLimit{})
+	m := &smocker.Mock{Proxy: &smocker.MockProxy{Host: "http://localhost"}}
+	require.NoError(t, Binance{}.BuildRateLimitResponse(*e, m, 1))
+	require.NotNil(t, m.Response)
+	require.Nil(t, m.Proxy)
+	require.Equal(t, http.StatusTooManyRequests, m.Response.Status)
+	require.Equal(t, smocker.StringSlice{"2"}, m.Response.Headers["X-MBX-USED-WEIGHT"])
+}
@@ -1,10 +1,8 @@
 package origin
 
 import (
-	"fmt"
 	"math/big"
 
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 
 	"github.com/chronicleprotocol/infestor/smocker"
@@ -33,47 +31,19 @@ func (b RocketPool) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 }
 
 func (b RocketPool) buildGetExchangeRate(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
-	}
-	funcData, ok := e.Custom["getExchangeRate"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
-		return nil, fmt.Errorf("not found function data for getExchangeRate")
-	}
-
-	var calls []MultiCall
-	var data []any
-	for i := 0; i < len(funcData); i++ {
-		getExchangeRateData, _ := getExchangeRate.EncodeArgs()
-		calls = append(calls, MultiCall{
-			Target: funcData[i].Address,
-			Data:   getExchangeRateData,
-		})
-		rate := funcData[i].Return[0].(*big.Int)
-		data = append(data, types.Bytes(rate.Bytes()).PadLeft(32))
-	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
+	return buildUint256Calls(e, "getExchangeRate", func(FunctionData) ([]byte, error) {
+		return getExchangeRate.EncodeArgs()
+	})
+}
 
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
+// RocketPoolToken is the rETH token and its exchange rate to ETH.
+type RocketPoolToken struct {
+	Address      types.Address
+	ExchangeRate *big.Int
+}
 
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+func (t RocketPoolToken) FunctionData() map[string][]FunctionData {
+	return map[string][]FunctionData{
+		"getExchangeRate": {{Address: t.Address, Args: []any{}, Return: []any{t.ExchangeRate}}},
+	}
 }
@@ -0,0 +1,74 @@
+package origin
+
+import (
+	"fmt"
+
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// Step is a response in a sequence, see ExchangeMock.WithSequence.
+type Step struct {
+	// Times is the number of calls the step responds to, 1 if 0.
+	Times uint
+	// Apply changes a copy of the exchange mock for the step, e.g. sets the price
+	// or the status code.
+	Apply func(e *ExchangeMock)
+}
+
+// WithSequence makes every endpoint of the exchange respond with the steps in
+// order before responding with the exchange mock itself, e.g. two 500 errors,
+// then the price 100, then the price 101:
+//
+//	origin.NewExchange("binance").WithSymbol("BTC/USDT").WithPrice(101).WithSequence(
+//		origin.Step{Times: 2, Apply: func(e *origin.ExchangeMock) { e.WithStatusCode(500) }},
+//		origin.Step{Apply: func(e *origin.ExchangeMock) { e.WithPrice(100) }},
+//	)
+func (e *ExchangeMock) WithSequence(steps ...Step) *ExchangeMock {
+	e.Sequence = append(e.Sequence, steps...)
+	return e
+}
+
+// step returns the exchange mock for the i-th step of the sequence.
+func (e ExchangeMock) step(i int) ExchangeMock {
+	s := e
+	s.Sequence = nil
+	s.Custom = make(map[string]any, len(e.Custom))
+	for k, v := range e.Custom {
+		s.Custom[k] = v
+	}
+	if apply := e.Sequence[i].Apply; apply != nil {
+		apply(&s)
+	}
+	return withDecimals([]ExchangeMock{s})[0]
+}
+
+// buildSequence builds mocks for the steps of the exchange mock sequence with
+// times limits. Smocker prefers mocks added later, so they are returned in
+// reverse order and must be added after the mock of the exchange mock itself.
+// Mocks of steps after the first one are auxiliary.
+func buildSequence(e ExchangeMock, f MockableFunc) ([]*smocker.Mock, error) {
+	var mocks []*smocker.Mock
+	for i := len(e.Sequence) - 1; i >= 0; i-- {
+		s := e.step(i)
+		m, err := buildMock(s, f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step %d: %w", i, err)
+		}
+		if m == nil {
+			continue
+		}
+		times := e.Sequence[i].Times
+		if times == 0 {
+			times = 1
+		}
+		m.Context = &smocker.MockContext{Times: times}
+		if err := applyDelay(s, m); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			auxiliary([]*smocker.Mock{m})
+		}
+		mocks = append(mocks, m)
+	}
+	return mocks, nil
+}
@@ -0,0 +1,67 @@
+package origin
+
+import (
+	"sort"
+	"time"
+)
+
+// TradeSide is the side of the taker of a trade.
+type TradeSide string
+
+const (
+	TradeBuy  TradeSide = "buy"
+	TradeSell TradeSide = "sell"
+)
+
+// Trade is a public trade served by trades endpoints of origins.
+type Trade struct {
+	// ID of the trade, trades without one are numbered from 1 in time order.
+	ID     int64
+	Time   time.Time
+	Price  Decimal
+	Amount Decimal
+	Side   TradeSide
+}
+
+// NewTrade returns the trade made at t.
+func NewTrade(t time.Time, price, amount float64, side TradeSide) Trade {
+	return Trade{Time: t, Price: NewDecimal(price), Amount: NewDecimal(amount), Side: side}
+}
+
+// WithTrades sets recent trades served by trades endpoints of the exchange.
+// Trades endpoints are mocked only for exchange mocks with trades.
+func (e *ExchangeMock) WithTrades(trades ...Trade) *ExchangeMock {
+	e.Trades = append(e.Trades, trades...)
+	return e
+}
+
+// trades returns trades of the exchange mock in ascending order for the limit,
+// false if there are no trades to serve. See limitItems.
+func (e ExchangeMock) trades(limit, def int) ([]Trade, bool) {
+	if len(e.Trades) == 0 {
+		return nil, false
+	}
+	n, ok := limitItems(len(e.Trades), limit, def)
+	if !ok {
+		return nil, false
+	}
+	trades := append([]Trade{}, e.Trades...)
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time.Before(trades[j].Time) })
+	for i := range trades {
+		if trades[i].ID == 0 {
+			trades[i].ID = int64(i + 1)
+		}
+	}
+	return trades[len(trades)-n:], true
+}
+
+// withTradesDecimals returns a copy of trades with values formatted with n decimals.
+func withTradesDecimals(trades []Trade, n int) []Trade {
+	res := make([]Trade, len(trades))
+	for i, t := range trades {
+		t.Price = t.Price.WithDecimals(n)
+		t.Amount = t.Amount.WithDecimals(n)
+		res[i] = t
+	}
+	return res
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/chronicleprotocol/infestor/smocker"
 	"github.com/defiweb/go-eth/abi"
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -39,12 +38,28 @@ func (b UniswapV2) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 }
 
 func (b UniswapV2) buildGetReserves(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
+	}
+	return buildGetReserves(e, blockNumber)
+}
+
+func (b UniswapV2) buildToken0(e ExchangeMock) (*smocker.Mock, error) {
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
+	}
+	return buildTokens(e, blockNumber)
+}
+
+// buildGetReserves builds the getReserves mock shared by Uniswap V2 forks.
+func buildGetReserves(e ExchangeMock, blockNumber int64) (*smocker.Mock, error) {
+	funcData, err := e.functionData("getReserves")
+	if err != nil {
+		return nil, err
 	}
-	funcData, ok := e.Custom["getReserves"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
+	if len(funcData) < 1 {
 		return nil, fmt.Errorf("not found function data for getReserves")
 	}
 
@@ -56,47 +71,45 @@ func (b UniswapV2) buildGetReserves(e ExchangeMock) (*smocker.Mock, error) {
 			Target: funcData[i].Address,
 			Data:   getReservesData,
 		})
-		reserve0 := funcData[i].Return[0].(*big.Int)
-		reserve1 := funcData[i].Return[1].(*big.Int)
-		blockTimestamp := funcData[i].Return[2].(*big.Int)
-		data = append(data, abi.MustEncodeValues(getReserves.Outputs(), reserve0, reserve1, blockTimestamp))
-	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+		reserves, err := encodeReserves(funcData[i])
+		if err != nil {
+			return nil, functionDataError("getReserves", i, err)
+		}
+		data = append(data, reserves)
+	}
+	return multiCallMock(e, blockNumber, calls, data)
 }
 
-func (b UniswapV2) buildToken0(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+func encodeReserves(f FunctionData) ([]byte, error) {
+	reserve0, err := returnAt[*big.Int](f, 0, "reserve0")
+	if err != nil {
+		return nil, err
+	}
+	reserve1, err := returnAt[*big.Int](f, 1, "reserve1")
+	if err != nil {
+		return nil, err
+	}
+	blockTimestamp, err := returnAt[*big.Int](f, 2, "blockTimestampLast")
+	if err != nil {
+		return nil, err
+	}
+	return abi.EncodeValues(getReserves.Outputs(), reserve0, reserve1, blockTimestamp)
+}
+
+// buildTokens builds the token0 and token1 mock shared by Uniswap pools.
+func buildTokens(e ExchangeMock, blockNumber int64) (*smocker.Mock, error) {
+	token0FuncData, err := e.functionData("token0")
+	if err != nil {
+		return nil, err
 	}
-	token0FuncData, ok := e.Custom["token0"].([]FunctionData)
-	if !ok || len(token0FuncData) < 1 {
+	if len(token0FuncData) < 1 {
 		return nil, fmt.Errorf("not found function data for token0")
 	}
-	token1FuncData, ok := e.Custom["token1"].([]FunctionData)
-	if !ok || len(token1FuncData) < 1 {
+	token1FuncData, err := e.functionData("token1")
+	if err != nil {
+		return nil, err
+	}
+	if len(token1FuncData) < 1 {
 		return nil, fmt.Errorf("not found function data for token1")
 	}
 	if len(token0FuncData) != len(token1FuncData) {
@@ -115,32 +128,38 @@ func (b UniswapV2) buildToken0(e ExchangeMock) (*smocker.Mock, error) {
 			Target: token1FuncData[i].Address,
 			Data:   token1Data,
 		})
-		token0 := token0FuncData[i].Return[0].(types.Address)
-		token1 := token1FuncData[i].Return[0].(types.Address)
+		token0, err := returnAt[types.Address](token0FuncData[i], 0, "token0")
+		if err != nil {
+			return nil, functionDataError("token0", i, err)
+		}
+		token1, err := returnAt[types.Address](token1FuncData[i], 0, "token1")
+		if err != nil {
+			return nil, functionDataError("token1", i, err)
+		}
 		data = append(data, types.Bytes(token0.Bytes()).PadLeft(32),
 			types.Bytes(token1.Bytes()).PadLeft(32))
 	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+	return multiCallMock(e, blockNumber, calls, data)
+}
+
+// UniswapV2Pool is a Uniswap V2 (or Sushiswap) pool, its reserves and tokens.
+type UniswapV2Pool struct {
+	Address            types.Address
+	Token0             types.Address
+	Token1             types.Address
+	Reserve0           *big.Int
+	Reserve1           *big.Int
+	BlockTimestampLast uint32
+}
+
+func (p UniswapV2Pool) FunctionData() map[string][]FunctionData {
+	return map[string][]FunctionData{
+		"getReserves": {{
+			Address: p.Address,
+			Args:    []any{},
+			Return:  []any{p.Reserve0, p.Reserve1, big.NewInt(int64(p.BlockTimestampLast))},
+		}},
+		"token0": {{Address: p.Address, Args: []any{}, Return: []any{p.Token0}}},
+		"token1": {{Address: p.Address, Args: []any{}, Return: []any{p.Token1}}},
+	}
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/chronicleprotocol/infestor/smocker"
 	"github.com/defiweb/go-eth/abi"
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -39,12 +38,15 @@ func (b UniswapV3) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 }
 
 func (b UniswapV3) buildSlot0(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
+	}
+	funcData, err := e.functionData("slot0")
+	if err != nil {
+		return nil, err
 	}
-	funcData, ok := e.Custom["slot0"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
+	if len(funcData) < 1 {
 		return nil, fmt.Errorf("not found function data for slot0")
 	}
 
@@ -56,104 +58,95 @@ func (b UniswapV3) buildSlot0(e ExchangeMock) (*smocker.Mock, error) {
 			Target: funcData[i].Address,
 			Data:   slot0Data,
 		})
-		sqrtPriceX96 := funcData[i].Return[0].(*big.Int)
-		tick := funcData[i].Return[1].(*big.Int)
-		observationIndex := funcData[i].Return[2].(*big.Int)
-		observationCardinality := funcData[i].Return[3].(*big.Int)
-		observationCardinalityNext := funcData[i].Return[4].(*big.Int)
-		feeProtocol := funcData[i].Return[5].(int)
-		unlocked := funcData[i].Return[6].(bool)
-		slot0Bytes := abi.MustEncodeValues(slot0.Outputs(),
-			sqrtPriceX96,
-			tick,
-			observationIndex,
-			observationCardinality,
-			observationCardinalityNext,
-			feeProtocol,
-			unlocked)
+		slot0Bytes, err := encodeSlot0(funcData[i])
+		if err != nil {
+			return nil, functionDataError("slot0", i, err)
+		}
 		data = append(data, slot0Bytes)
 	}
-
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
-
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
-
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+	return multiCallMock(e, blockNumber, calls, data)
 }
 
-func (b UniswapV3) buildToken0(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
+func encodeSlot0(f FunctionData) ([]byte, error) {
+	if len(f.Return) != 7 { //nolint:gomnd
+		return nil, fmt.Errorf("slot0 returns 7 values, got %d", len(f.Return))
 	}
-	token0FuncData, ok := e.Custom["token0"].([]FunctionData)
-	if !ok || len(token0FuncData) < 1 {
-		return nil, fmt.Errorf("not found function data for token0")
+	sqrtPriceX96, err := returnAt[*big.Int](f, 0, "sqrtPriceX96")
+	if err != nil {
+		return nil, err
+	}
+	tick, err := returnAt[*big.Int](f, 1, "tick")
+	if err != nil {
+		return nil, err
 	}
-	token1FuncData, ok := e.Custom["token1"].([]FunctionData)
-	if !ok || len(token1FuncData) < 1 {
-		return nil, fmt.Errorf("not found function data for token1")
+	observationIndex, err := returnAt[*big.Int](f, 2, "observationIndex")
+	if err != nil {
+		return nil, err
 	}
-	if len(token0FuncData) != len(token1FuncData) {
-		return nil, fmt.Errorf("not found function data for token0 and token1")
+	observationCardinality, err := returnAt[*big.Int](f, 3, "observationCardinality")
+	if err != nil {
+		return nil, err
 	}
+	observationCardinalityNext, err := returnAt[*big.Int](f, 4, "observationCardinalityNext")
+	if err != nil {
+		return nil, err
+	}
+	feeProtocol, err := returnAt[int](f, 5, "feeProtocol")
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := returnAt[bool](f, 6, "unlocked")
+	if err != nil {
+		return nil, err
+	}
+	return abi.EncodeValues(slot0.Outputs(),
+		sqrtPriceX96,
+		tick,
+		observationIndex,
+		observationCardinality,
+		observationCardinalityNext,
+		feeProtocol,
+		unlocked)
+}
 
-	token0Data, _ := token0Abi.EncodeArgs()
-	token1Data, _ := token1Abi.EncodeArgs()
-	var calls []MultiCall
-	var data []any
-	for i := 0; i < len(token0FuncData); i++ {
-		calls = append(calls, MultiCall{
-			Target: token0FuncData[i].Address,
-			Data:   token0Data,
-		}, MultiCall{
-			Target: token1FuncData[i].Address,
-			Data:   token1Data,
-		})
-		token0 := token0FuncData[i].Return[0].(types.Address)
-		token1 := token1FuncData[i].Return[0].(types.Address)
-		data = append(data, types.Bytes(token0.Bytes()).PadLeft(32),
-			types.Bytes(token1.Bytes()).PadLeft(32))
+func (b UniswapV3) buildToken0(e ExchangeMock) (*smocker.Mock, error) {
+	blockNumber, err := e.blockNumber()
+	if err != nil {
+		return nil, err
 	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
+	return buildTokens(e, blockNumber)
+}
 
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
+// UniswapV3Pool is a Uniswap V3 pool, its `slot0` and tokens.
+type UniswapV3Pool struct {
+	Address                    types.Address
+	Token0                     types.Address
+	Token1                     types.Address
+	SqrtPriceX96               *big.Int
+	Tick                       int32
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}
 
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
+func (p UniswapV3Pool) FunctionData() map[string][]FunctionData {
+	return map[string][]FunctionData{
+		"slot0": {{
+			Address: p.Address,
+			Args:    []any{},
+			Return: []any{
+				p.SqrtPriceX96,
+				big.NewInt(int64(p.Tick)),
+				big.NewInt(int64(p.ObservationIndex)),
+				big.NewInt(int64(p.ObservationCardinality)),
+				big.NewInt(int64(p.ObservationCardinalityNext)),
+				int(p.FeeProtocol),
+				p.Unlocked,
 			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+		}},
+		"token0": {{Address: p.Address, Args: []any{}, Return: []any{p.Token0}}},
+		"token1": {{Address: p.Address, Args: []any{}, Return: []any{p.Token1}}},
+	}
 }
@@ -2,6 +2,7 @@ package origin
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/chronicleprotocol/infestor/smocker"
 )
@@ -12,6 +13,13 @@ func (u Upbit) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 	return CombineMocks(e, u.build)
 }
 
+func (u Upbit) BuildErrorResponse(e ExchangeMock, m *smocker.Mock) error {
+	ee := e.Error.withDefaults(http.StatusNotFound, "404", "Code not found")
+	setErrorResponse(m, ee.StatusCode, fmt.Sprintf(`{"error":{"name":%s,"message":%s}}`,
+		jsonString(ee.Code), jsonString(ee.Message)))
+	return nil
+}
+
 func (u Upbit) build(e ExchangeMock) (*smocker.Mock, error) {
 	symbol := fmt.Sprintf("%s-%s", e.Symbol.Quote, e.Symbol.Base)
 	body := `[
@@ -4,10 +4,8 @@ package origin
 // https://etherscan.io/address/0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0#code
 
 import (
-	"fmt"
 	"math/big"
 
-	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 
 	"github.com/chronicleprotocol/infestor/smocker"
@@ -36,47 +34,19 @@ func (b WSTETH) BuildMocks(e []ExchangeMock) ([]*smocker.Mock, error) {
 }
 
 func (b WSTETH) buildSTEthPerToken(e ExchangeMock) (*smocker.Mock, error) {
-	blockNumber, err := e.Custom["blockNumber"].(int)
-	if !err {
-		return nil, fmt.Errorf("not found block number")
-	}
-	funcData, ok := e.Custom["stEthPerToken"].([]FunctionData)
-	if !ok || len(funcData) < 1 {
-		return nil, fmt.Errorf("not found function data for stEthPerToken")
-	}
-
-	var calls []MultiCall
-	var data []any
-	for i := 0; i < len(funcData); i++ {
-		stEthPerTokenData, _ := stEthPerToken.EncodeArgs()
-		calls = append(calls, MultiCall{
-			Target: funcData[i].Address,
-			Data:   stEthPerTokenData,
-		})
-		rate := funcData[i].Return[0].(*big.Int)
-		data = append(data, types.Bytes(rate.Bytes()).PadLeft(32))
-	}
-	args, _ := encodeMultiCallArgs(calls)
-	resp, _ := encodeMultiCallResponse(int64(blockNumber), data)
+	return buildUint256Calls(e, "stEthPerToken", func(FunctionData) ([]byte, error) {
+		return stEthPerToken.EncodeArgs()
+	})
+}
 
-	m := smocker.ShouldContainSubstring(hexutil.BytesToHex(args))
+// WSTETHToken is the wstETH token and its stETH per token rate.
+type WSTETHToken struct {
+	Address       types.Address
+	StETHPerToken *big.Int
+}
 
-	return &smocker.Mock{
-		Request: smocker.MockRequest{
-			Method: smocker.ShouldEqual("POST"),
-			Path:   smocker.ShouldEqual("/"),
-			Body: &smocker.BodyMatcher{
-				BodyString: &m,
-			},
-		},
-		Response: &smocker.MockResponse{
-			Status: e.StatusCode,
-			Headers: map[string]smocker.StringSlice{
-				"Content-Type": []string{
-					"application/json",
-				},
-			},
-			Body: fmt.Sprintf(RPCJSONResult, hexutil.BytesToHex(resp)),
-		},
-	}, nil
+func (t WSTETHToken) FunctionData() map[string][]FunctionData {
+	return map[string][]FunctionData{
+		"stEthPerToken": {{Address: t.Address, Args: []any{}, Return: []any{t.StETHPerToken}}},
+	}
 }
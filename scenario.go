@@ -0,0 +1,146 @@
+package infestor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// Scenario describes a set of mocks to deploy. It is loaded from a YAML (or JSON) file:
+//
+//	reset: true
+//	session: ethbtc
+//	exchanges:
+//	  - name: binance
+//	    symbol: ETH/BTC
+//	    price: 1
+//	  - name: ethrpc
+//	    custom:
+//	      blockNumber: 100
+//	proxies:
+//	  - path: {matcher: ShouldMatch, value: ".*"}
+//	    host: http://localhost:8545
+//	mocks:
+//	  - fixtures/extra.yaml
+type Scenario struct {
+	Reset bool `yaml:"reset"`
+	// KeepLocked makes the reset keep locked mocks.
+	KeepLocked bool               `yaml:"keepLocked"`
+	Lock       bool               `yaml:"lock"`
+	Session    string             `yaml:"session"`
+	Exchanges  []ScenarioExchange `yaml:"exchanges"`
+	Proxies    []ScenarioProxy    `yaml:"proxies"`
+	// Mocks are files with smocker mocks, relative paths are resolved against the scenario file.
+	Mocks []string `yaml:"mocks"`
+
+	dir string
+}
+
+type ScenarioExchange struct {
+	Name   string `yaml:"name"`
+	Symbol string `yaml:"symbol"`
+	Status int    `yaml:"status"`
+	// Prices and volumes are read exactly as written, e.g. `price: 0.000000012345`.
+	Price     origin.Decimal `yaml:"price"`
+	Volume    origin.Decimal `yaml:"volume"`
+	Ask       origin.Decimal `yaml:"ask"`
+	Bid       origin.Decimal `yaml:"bid"`
+	Decimals  *int           `yaml:"decimals"`
+	Timestamp *time.Time     `yaml:"timestamp"`
+	Custom    map[string]any `yaml:"custom"`
+}
+
+type ScenarioProxy struct {
+	Path smocker.StringMatcher `yaml:"path"`
+	Host string                `yaml:"host"`
+}
+
+// LoadScenario reads a scenario from the YAML or JSON file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario %s: %w", path, err)
+	}
+	var s Scenario
+	// JSON is valid YAML, so a single decoder handles both.
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scenario %s: %w", path, err)
+	}
+	s.dir = filepath.Dir(path)
+	return &s, nil
+}
+
+// MocksBuilder returns a builder with everything described by the scenario.
+func (s *Scenario) MocksBuilder() (*MocksBuilder, error) {
+	mb := NewMocksBuilder()
+	if s.Reset {
+		mb.Reset()
+	}
+	if s.KeepLocked {
+		mb.KeepLocked()
+	}
+	if s.Lock {
+		mb.Lock()
+	}
+	if s.Session != "" {
+		mb.Session(s.Session)
+	}
+	for _, p := range s.Proxies {
+		mb.Proxy(p.Path, p.Host)
+	}
+	for _, path := range s.Mocks {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(s.dir, path)
+		}
+		mocks, err := smocker.LoadMocks(path)
+		if err != nil {
+			return nil, err
+		}
+		mb.AddMocks(mocks...)
+	}
+	for i, se := range s.Exchanges {
+		e, err := se.exchangeMock()
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange #%d: %w", i, err)
+		}
+		mb.Add(e)
+	}
+	return mb, nil
+}
+
+func (se ScenarioExchange) exchangeMock() (*origin.ExchangeMock, error) {
+	if se.Name == "" {
+		return nil, fmt.Errorf("exchange name is required")
+	}
+	e := origin.NewExchange(se.Name).
+		WithPriceDecimal(se.Price).
+		WithVolumeDecimal(se.Volume).
+		WithAskDecimal(se.Ask).
+		WithBidDecimal(se.Bid)
+	if se.Symbol != "" {
+		if strings.Count(se.Symbol, "/") != 1 {
+			return nil, fmt.Errorf("invalid symbol %q, expected BASE/QUOTE", se.Symbol)
+		}
+		e.WithSymbol(se.Symbol)
+	}
+	if se.Decimals != nil {
+		e.WithDecimals(*se.Decimals)
+	}
+	if se.Status != 0 {
+		e.WithStatusCode(se.Status)
+	}
+	if se.Timestamp != nil {
+		e.WithTime(*se.Timestamp)
+	}
+	for key, value := range se.Custom {
+		e.WithCustom(key, value)
+	}
+	return e, nil
+}
@@ -35,8 +35,71 @@ func (a *API) Reset(ctx context.Context) error {
 	return nil
 }
 
+// ResetUnlocked Clear the mocks and the history of calls, but keep locked mocks.
+func (a *API) ResetUnlocked(ctx context.Context) error {
+	request := Request{
+		Method:  Post,
+		BaseURL: fmt.Sprintf("%s/reset?force=false", a.URL),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("reset failed: %s", res.Body)
+	}
+	return nil
+}
+
+// LockMocks Lock mocks with given IDs, locked mocks survive ResetUnlocked.
+func (a *API) LockMocks(ctx context.Context, ids []string) ([]*Mock, error) {
+	return a.setLocked(ctx, "lock", ids)
+}
+
+// UnlockMocks Unlock mocks with given IDs.
+func (a *API) UnlockMocks(ctx context.Context, ids []string) ([]*Mock, error) {
+	return a.setLocked(ctx, "unlock", ids)
+}
+
+func (a *API) setLocked(ctx context.Context, action string, ids []string) ([]*Mock, error) {
+	body, err := json.Marshal(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mock ids: %w", err)
+	}
+	request := Request{
+		Method:  Post,
+		BaseURL: fmt.Sprintf("%s/mocks/%s", a.URL, action),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: body,
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to %s mocks: %s", action, res.Body)
+	}
+	var mocks []*Mock
+	if err := json.Unmarshal([]byte(res.Body), &mocks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mocks: %w", err)
+	}
+	return mocks, nil
+}
+
 // AddMocks Add a mock to the mocks list.
 func (a *API) AddMocks(ctx context.Context, mock []*Mock) error {
+	return a.AddSessionMocks(ctx, "", mock)
+}
+
+// AddSessionMocks Add a mock to the mocks list of the session with given ID,
+// or of the current session if the ID is empty.
+func (a *API) AddSessionMocks(ctx context.Context, sessionID string, mock []*Mock) error {
 	body, err := json.Marshal(mock)
 	if err != nil {
 		return fmt.Errorf("failed to marshal mocks request: %w", err)
@@ -49,6 +112,9 @@ func (a *API) AddMocks(ctx context.Context, mock []*Mock) error {
 		},
 		Body: body,
 	}
+	if sessionID != "" {
+		request.QueryParams = map[string]string{"session": sessionID}
+	}
 	res, err := SendWithContext(ctx, request)
 	if err != nil {
 		return err
@@ -58,3 +124,32 @@ func (a *API) AddMocks(ctx context.Context, mock []*Mock) error {
 	}
 	return nil
 }
+
+// Mocks Get the list of registered mocks.
+func (a *API) Mocks(ctx context.Context) ([]*Mock, error) {
+	return a.SessionMocks(ctx, "")
+}
+
+// SessionMocks Get the list of mocks registered in the session with given ID,
+// or in the current session if the ID is empty.
+func (a *API) SessionMocks(ctx context.Context, sessionID string) ([]*Mock, error) {
+	request := Request{
+		Method:  Get,
+		BaseURL: fmt.Sprintf("%s/mocks", a.URL),
+	}
+	if sessionID != "" {
+		request.QueryParams = map[string]string{"session": sessionID}
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get mocks: %s", res.Body)
+	}
+	var mocks []*Mock
+	if err := json.Unmarshal([]byte(res.Body), &mocks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mocks: %w", err)
+	}
+	return mocks, nil
+}
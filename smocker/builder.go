@@ -83,33 +83,65 @@ func (mb *MockBuilder) AddRequestHeader(name string, value StringMatcher) *MockB
 }
 
 func (mb *MockBuilder) SetResponseStatus(status int) *MockBuilder {
-	mb.mock.Response.Status = status
+	mb.response().Status = status
 	return mb
 }
 
 func (mb *MockBuilder) SetResponseHeaders(headers MapStringSlice) *MockBuilder {
-	if mb.mock.Response.Headers == nil {
-		mb.mock.Response.Headers = make(MapStringSlice)
+	if mb.response().Headers == nil {
+		mb.response().Headers = make(MapStringSlice)
 	}
-	mb.mock.Response.Headers = headers
+	mb.response().Headers = headers
 	return mb
 }
 
 func (mb *MockBuilder) AddResponseHeader(name string, value string) *MockBuilder {
-	if mb.mock.Response.Headers == nil {
-		mb.mock.Response.Headers = make(MapStringSlice)
+	if mb.response().Headers == nil {
+		mb.response().Headers = make(MapStringSlice)
 	}
-	mb.mock.Response.Headers[name] = append(mb.mock.Response.Headers[name], value)
+	mb.response().Headers[name] = append(mb.response().Headers[name], value)
 	return mb
 }
 
 func (mb *MockBuilder) SetResponseDelay(min, max time.Duration) *MockBuilder {
-	mb.mock.Response.Delay = Delay{Min: min, Max: max}
+	mb.response().Delay = Delay{Min: min, Max: max}
 	return mb
 }
 
 func (mb *MockBuilder) SetResponseBody(body string) *MockBuilder {
-	mb.mock.Response.Body = body
+	mb.response().Body = body
+	return mb
+}
+
+// SetDynamicResponse replaces the static response with a response rendered by the given engine.
+func (mb *MockBuilder) SetDynamicResponse(engine Engine, script string) *MockBuilder {
+	mb.mock.Response = nil
+	mb.mock.Proxy = nil
+	mb.mock.DynamicResponse = &DynamicMockResponse{Engine: engine, Script: script}
+	return mb
+}
+
+// SetProxy replaces the response with forwarding of the request to the given host.
+func (mb *MockBuilder) SetProxy(host string) *MockBuilder {
+	mb.mock.Response = nil
+	mb.mock.DynamicResponse = nil
+	if mb.mock.Proxy == nil {
+		mb.mock.Proxy = &MockProxy{}
+	}
+	mb.mock.Proxy.Host = host
+	return mb
+}
+
+// AddProxyHeader adds a header to requests forwarded to the proxy host. It makes
+// the mock a proxy like SetProxy, the host may be set before or after.
+func (mb *MockBuilder) AddProxyHeader(name string, value string) *MockBuilder {
+	if mb.mock.Proxy == nil {
+		mb.SetProxy("")
+	}
+	if mb.mock.Proxy.Headers == nil {
+		mb.mock.Proxy.Headers = make(MapStringSlice)
+	}
+	mb.mock.Proxy.Headers[name] = append(mb.mock.Proxy.Headers[name], value)
 	return mb
 }
 
@@ -121,6 +153,16 @@ func (mb *MockBuilder) SetContextTimes(times uint) *MockBuilder {
 	return mb
 }
 
+// response returns the static response, replacing a previously set dynamic response or proxy.
+func (mb *MockBuilder) response() *MockResponse {
+	if mb.mock.Response == nil {
+		mb.mock.DynamicResponse = nil
+		mb.mock.Proxy = nil
+		mb.mock.Response = &MockResponse{Status: http.StatusOK}
+	}
+	return mb.mock.Response
+}
+
 func (mb *MockBuilder) Mock() *Mock {
 	return mb.mock
 }
@@ -0,0 +1,22 @@
+package smocker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockBuilderProxyHeader(t *testing.T) {
+	for name, mb := range map[string]*MockBuilder{
+		"header first": NewMockBuilder().AddProxyHeader("X-Api-Key", "key").SetProxy("http://localhost"),
+		"proxy first":  NewMockBuilder().SetProxy("http://localhost").AddProxyHeader("X-Api-Key", "key"),
+	} {
+		m := mb.Mock()
+		require.NoError(t, m.Validate(), name)
+		require.Nil(t, m.Response, name)
+		require.Equal(t, &MockProxy{
+			Host:    "http://localhost",
+			Headers: MapStringSlice{"X-Api-Key": {"key"}},
+		}, m.Proxy, name)
+	}
+}
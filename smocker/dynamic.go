@@ -0,0 +1,92 @@
+package smocker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateRequest is the request exposed to dynamic response templates as `.Request`.
+type templateRequest struct {
+	Path        string
+	Method      string
+	Body        any
+	QueryParams url.Values
+	Headers     http.Header
+}
+
+type templateData struct {
+	Request templateRequest
+}
+
+// renderedResponse is the output of a template. Unlike MockResponse, the body
+// may be any JSON/YAML value, in which case it is sent JSON encoded.
+type renderedResponse struct {
+	Status  int            `json:"status" yaml:"status"`
+	Body    any            `json:"body" yaml:"body"`
+	Headers MapStringSlice `json:"headers" yaml:"headers"`
+	Delay   Delay          `json:"delay" yaml:"delay"`
+}
+
+var templateFuncs = template.FuncMap{
+	"toJson": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// render renders the dynamic response for the request. Only Go template engines
+// are supported by the in-process server.
+func (d *DynamicMockResponse) render(req *http.Request, body string) (*MockResponse, error) {
+	tpl, err := template.New("dynamic_response").Funcs(templateFuncs).Parse(d.Script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dynamic response: %w", err)
+	}
+	data := templateData{Request: templateRequest{
+		Path:        req.URL.Path,
+		Method:      req.Method,
+		Body:        body,
+		QueryParams: req.URL.Query(),
+		Headers:     req.Header,
+	}}
+	var parsed any
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+		data.Request.Body = parsed
+	}
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("failed to execute dynamic response: %w", err)
+	}
+
+	var res renderedResponse
+	switch d.Engine {
+	case GoTemplateJSONEngine:
+		err = json.Unmarshal(out.Bytes(), &res)
+	case GoTemplateEngine, GoTemplateYAMLEngine:
+		err = yaml.Unmarshal(out.Bytes(), &res)
+	default:
+		return nil, fmt.Errorf("engine %s is not supported", d.Engine)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dynamic response: %w", err)
+	}
+
+	mr := &MockResponse{Status: res.Status, Headers: res.Headers, Delay: res.Delay}
+	switch b := res.Body.(type) {
+	case nil:
+	case string:
+		mr.Body = b
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal dynamic response body: %w", err)
+		}
+		mr.Body = string(encoded)
+	}
+	return mr, nil
+}
@@ -0,0 +1,175 @@
+package smocker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Smocker accepts shorthand forms in mock definitions: a plain string is a
+// ShouldEqual matcher and a single value may be used instead of a list. The
+// unmarshalers below accept them, so hand-written files can be loaded.
+
+func (sm *StringMatcher) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*sm = ShouldEqual(s)
+		return nil
+	}
+	type plain StringMatcher
+	return json.Unmarshal(data, (*plain)(sm))
+}
+
+func (sm *StringMatcher) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*sm = ShouldEqual(node.Value)
+		return nil
+	}
+	type plain StringMatcher
+	return node.Decode((*plain)(sm))
+}
+
+func (sms *StringMatcherSlice) UnmarshalJSON(data []byte) error {
+	var list []StringMatcher
+	if err := json.Unmarshal(data, &list); err == nil {
+		*sms = list
+		return nil
+	}
+	var single StringMatcher
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	*sms = StringMatcherSlice{single}
+	return nil
+}
+
+func (sms *StringMatcherSlice) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var list []StringMatcher
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*sms = list
+		return nil
+	}
+	var single StringMatcher
+	if err := node.Decode(&single); err != nil {
+		return err
+	}
+	*sms = StringMatcherSlice{single}
+	return nil
+}
+
+func (ss *StringSlice) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*ss = StringSlice{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*ss = list
+	return nil
+}
+
+func (ss *StringSlice) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*ss = StringSlice{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*ss = list
+	return nil
+}
+
+func (bm BodyMatcher) MarshalYAML() (any, error) {
+	if bm.BodyString != nil {
+		return bm.BodyString, nil
+	}
+	return bm.BodyJSON, nil
+}
+
+func (bm *BodyMatcher) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s := ShouldEqual(node.Value)
+		bm.BodyString = &s
+		return nil
+	}
+	var fields map[string]any
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	if _, ok := fields["matcher"]; ok {
+		var s StringMatcher
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		bm.BodyString = &s
+		return nil
+	}
+	return node.Decode(&bm.BodyJSON)
+}
+
+// MarshalYAML writes delays as duration strings, e.g. `min: 10ms`.
+func (d Delay) MarshalYAML() (any, error) {
+	res := map[string]string{}
+	if d.Min != 0 {
+		res["min"] = d.Min.String()
+	}
+	if d.Max != 0 {
+		res["max"] = d.Max.String()
+	}
+	return res, nil
+}
+
+// UnmarshalYAML accepts a single duration (`delay: 10ms`) or a min/max range
+// of durations. Integer values are nanoseconds.
+func (d *Delay) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		v, err := parseDuration(node)
+		if err != nil {
+			return err
+		}
+		d.Min, d.Max = v, v
+		return nil
+	}
+	var fields map[string]yaml.Node
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		value := value
+		v, err := parseDuration(&value)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "min":
+			d.Min = v
+		case "max":
+			d.Max = v
+		default:
+			return fmt.Errorf("unknown delay field: %s", key)
+		}
+	}
+	return nil
+}
+
+func parseDuration(node *yaml.Node) (time.Duration, error) {
+	var ns int64
+	if err := node.Decode(&ns); err == nil {
+		return time.Duration(ns), nil
+	}
+	d, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid delay %q: %w", node.Value, err)
+	}
+	return d, nil
+}
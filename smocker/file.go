@@ -0,0 +1,92 @@
+package smocker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a mocks file.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// FormatFromPath returns the format of the file by its extension. Files without
+// `.json` extension are treated as YAML, which is Smocker's native format.
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// MarshalMocks encodes mocks in the given format.
+func MarshalMocks(mocks []*Mock, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(mocks, "", "  ")
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2) //nolint:gomnd
+		if err := enc.Encode(mocks); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown mocks format: %s", format)
+}
+
+// UnmarshalMocks decodes mocks in the given format.
+func UnmarshalMocks(data []byte, format Format) ([]*Mock, error) {
+	var mocks []*Mock
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &mocks)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &mocks)
+	default:
+		return nil, fmt.Errorf("unknown mocks format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mocks, nil
+}
+
+// SaveMocks writes mocks to the file, the format is chosen by the file extension.
+func SaveMocks(path string, mocks []*Mock) error {
+	data, err := MarshalMocks(mocks, FormatFromPath(path))
+	if err != nil {
+		return fmt.Errorf("failed to marshal mocks: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("failed to write mocks file %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadMocks reads mocks from the file, the format is chosen by the file extension.
+func LoadMocks(path string) ([]*Mock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mocks file %s: %w", path, err)
+	}
+	mocks, err := UnmarshalMocks(data, FormatFromPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mocks file %s: %w", path, err)
+	}
+	return mocks, nil
+}
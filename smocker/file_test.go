@@ -0,0 +1,71 @@
+package smocker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadMocks(t *testing.T) {
+	mocks := []*Mock{
+		NewMockBuilder().
+			SetRequestMethod(ShouldEqual("GET")).
+			SetRequestPath(ShouldEqual("/api/v3/ticker/price")).
+			AddRequestQueryParam("symbol", ShouldEqual("ETHBTC")).
+			AddResponseHeader("Content-Type", "application/json").
+			SetResponseDelay(10*time.Millisecond, 20*time.Millisecond).
+			SetResponseBody(`{"symbol": "ETHBTC","price": "1.00000000"}`).
+			SetContextTimes(2).
+			Mock(),
+		NewMockBuilder().
+			SetRequestMethod(ShouldEqual("POST")).
+			SetRequestBodyString(ShouldContainSubstring("eth_chainId")).
+			SetDynamicResponse(GoTemplateJSONEngine, `{"status": 200}`).
+			Mock(),
+		NewMockBuilder().
+			AddRequestBodyJSON("method", ShouldEqual("eth_call")).
+			SetProxy("http://localhost:8545").
+			Mock(),
+	}
+
+	for _, name := range []string{"mocks.yaml", "mocks.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, SaveMocks(path, mocks))
+		loaded, err := LoadMocks(path)
+		require.NoError(t, err)
+		require.Equal(t, mocks, loaded, name)
+	}
+}
+
+func TestUnmarshalMocksYAMLShorthand(t *testing.T) {
+	data := `
+- request:
+    method: GET
+    path:
+      matcher: ShouldMatch
+      value: /v2/ticker/.*
+    query_params:
+      symbol: ETHBTC
+    body: some body
+  response:
+    status: 200
+    delay: 10ms
+    headers:
+      Content-Type: application/json
+    body: |
+      [1, 2]
+`
+	mocks, err := UnmarshalMocks([]byte(data), FormatYAML)
+	require.NoError(t, err)
+	require.Len(t, mocks, 1)
+	m := mocks[0]
+	require.Equal(t, ShouldEqual("GET"), m.Request.Method)
+	require.Equal(t, ShouldMatch("/v2/ticker/.*"), m.Request.Path)
+	require.Equal(t, StringMatcherSlice{ShouldEqual("ETHBTC")}, m.Request.QueryParams["symbol"])
+	require.Equal(t, ShouldEqual("some body"), *m.Request.Body.BodyString)
+	require.Equal(t, Delay{Min: 10 * time.Millisecond, Max: 10 * time.Millisecond}, m.Response.Delay)
+	require.Equal(t, StringSlice{"application/json"}, m.Response.Headers["Content-Type"])
+	require.Equal(t, "[1, 2]\n", m.Response.Body)
+}
@@ -0,0 +1,168 @@
+package smocker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// History is the list of calls received by Smocker, oldest first.
+type History []*HistoryEntry
+
+// HistoryEntry is a single request/response pair recorded by Smocker.
+type HistoryEntry struct {
+	Context  HistoryContext  `json:"context"`
+	Request  HistoryRequest  `json:"request"`
+	Response HistoryResponse `json:"response"`
+	// Mock is the mock that served the request, nil if no mock matched.
+	Mock *Mock `json:"-"`
+}
+
+type HistoryContext struct {
+	MockID   string `json:"mock_id,omitempty"`
+	MockType string `json:"mock_type,omitempty"`
+}
+
+type HistoryRequest struct {
+	Path        string              `json:"path"`
+	Method      string              `json:"method"`
+	Body        HistoryBody         `json:"body,omitempty"`
+	QueryParams map[string][]string `json:"query_params,omitempty"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	Origin      string              `json:"origin,omitempty"`
+	Date        time.Time           `json:"date"`
+}
+
+type HistoryResponse struct {
+	Status  int                 `json:"status"`
+	Body    HistoryBody         `json:"body,omitempty"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Date    time.Time           `json:"date"`
+}
+
+// HistoryBody is a recorded body. Smocker stores JSON bodies as JSON values
+// and everything else as strings, HistoryBody accepts both.
+type HistoryBody string
+
+func (b HistoryBody) MarshalJSON() ([]byte, error) {
+	if json.Valid([]byte(b)) {
+		return []byte(b), nil
+	}
+	return json.Marshal(string(b))
+}
+
+func (b *HistoryBody) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = HistoryBody(s)
+		return nil
+	}
+	*b = HistoryBody(data)
+	return nil
+}
+
+// URL returns the requested path together with its query string.
+func (r HistoryRequest) URL() string {
+	if len(r.QueryParams) == 0 {
+		return r.Path
+	}
+	return r.Path + "?" + url.Values(r.QueryParams).Encode()
+}
+
+// HTTPRequest rebuilds the recorded request, so it can be evaluated by MockRequest.Match.
+func (r HistoryRequest) HTTPRequest() *http.Request {
+	return &http.Request{
+		Method: r.Method,
+		URL:    &url.URL{Path: r.Path, RawQuery: url.Values(r.QueryParams).Encode()},
+		Header: r.Headers,
+	}
+}
+
+// Filter returns entries for which f returns true.
+func (h History) Filter(f func(*HistoryEntry) bool) History {
+	var res History
+	for _, e := range h {
+		if f(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// FilterByPath returns entries requesting given path. The path may contain
+// a query string, e.g. `/api/v3/ticker/price?symbol=ETHBTC`, in which case
+// the listed query params have to be present in the request as well.
+func (h History) FilterByPath(path string) History {
+	p, rawQuery, _ := strings.Cut(path, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	return h.Filter(func(e *HistoryEntry) bool {
+		if e.Request.Path != p {
+			return false
+		}
+		for key, values := range query {
+			if !StringSlice(values).equal(e.Request.QueryParams[key]) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (ss StringSlice) equal(values []string) bool {
+	if len(ss) != len(values) {
+		return false
+	}
+	for i := range ss {
+		if ss[i] != values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// History returns the calls received by Smocker in the current session with the mocks that served them.
+func (a *API) History(ctx context.Context) (History, error) {
+	return a.SessionHistory(ctx, "")
+}
+
+// SessionHistory returns the calls received by Smocker in the session with given ID,
+// or in the current session if the ID is empty.
+func (a *API) SessionHistory(ctx context.Context, sessionID string) (History, error) {
+	request := Request{
+		Method:  Get,
+		BaseURL: fmt.Sprintf("%s/history", a.URL),
+	}
+	if sessionID != "" {
+		request.QueryParams = map[string]string{"session": sessionID}
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get history: %s", res.Body)
+	}
+	var history History
+	if err := json.Unmarshal([]byte(res.Body), &history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal history: %w", err)
+	}
+
+	mocks, err := a.SessionMocks(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Mock, len(mocks))
+	for _, m := range mocks {
+		if m.State != nil {
+			byID[m.State.ID] = m
+		}
+	}
+	for _, e := range history {
+		e.Mock = byID[e.Context.MockID]
+	}
+	return history, nil
+}
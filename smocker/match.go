@@ -0,0 +1,176 @@
+package smocker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Match reports whether value satisfies the matcher. Matchers are evaluated
+// the same way Smocker evaluates them; an empty matcher defaults to ShouldEqual.
+func (sm StringMatcher) Match(value string) bool {
+	switch sm.Matcher {
+	case "", "ShouldEqual", "ShouldResemble":
+		return value == sm.Value
+	case "ShouldNotEqual", "ShouldNotResemble":
+		return value != sm.Value
+	case "ShouldEqualJSON":
+		return equalJSON(value, sm.Value)
+	case "ShouldContainSubstring":
+		return strings.Contains(value, sm.Value)
+	case "ShouldNotContainSubstring":
+		return !strings.Contains(value, sm.Value)
+	case "ShouldStartWith":
+		return strings.HasPrefix(value, sm.Value)
+	case "ShouldNotStartWith":
+		return !strings.HasPrefix(value, sm.Value)
+	case "ShouldEndWith":
+		return strings.HasSuffix(value, sm.Value)
+	case "ShouldNotEndWith":
+		return !strings.HasSuffix(value, sm.Value)
+	case "ShouldMatch":
+		re, err := regexp.Compile(sm.Value)
+		return err == nil && re.MatchString(value)
+	case "ShouldNotMatch":
+		re, err := regexp.Compile(sm.Value)
+		return err == nil && !re.MatchString(value)
+	case "ShouldBeEmpty":
+		return value == ""
+	case "ShouldNotBeEmpty":
+		return value != ""
+	}
+	return false
+}
+
+// Match reports whether values are matched one by one by the matchers.
+func (sms StringMatcherSlice) Match(values []string) bool {
+	if len(values) != len(sms) {
+		return false
+	}
+	for i, v := range values {
+		if !sms[i].Match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether every key of the matcher matches the values stored under the same key.
+func (mmm MultiMapMatcher) Match(values map[string][]string) bool {
+	for key, matchers := range mmm {
+		if !matchers.Match(values[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether the body satisfies the matcher. JSON matchers use dot
+// separated keys to address nested fields, e.g. `params.0.to`.
+func (bm BodyMatcher) Match(body string) bool {
+	if bm.BodyString != nil {
+		return bm.BodyString.Match(body)
+	}
+	if len(bm.BodyJSON) == 0 {
+		return true
+	}
+	var data any
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return false
+	}
+	for key, matcher := range bm.BodyJSON {
+		value, ok := lookupJSON(data, strings.Split(key, "."))
+		if !ok || !matcher.Match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether the HTTP request satisfies the mock request.
+// The request body has to be passed separately because it can be read only once.
+func (mr MockRequest) Match(req *http.Request, body string) bool {
+	if mr.Method.Value != "" && !mr.Method.Match(req.Method) {
+		return false
+	}
+	if !mr.Path.Match(req.URL.Path) {
+		return false
+	}
+	if mr.QueryParams != nil && !mr.QueryParams.Match(req.URL.Query()) {
+		return false
+	}
+	if mr.Headers != nil {
+		headers := make(MultiMapMatcher, len(mr.Headers))
+		for key, matchers := range mr.Headers {
+			headers[http.CanonicalHeaderKey(key)] = matchers
+		}
+		if !headers.Match(req.Header) {
+			return false
+		}
+	}
+	if mr.Body != nil && !mr.Body.Match(body) {
+		return false
+	}
+	return true
+}
+
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func equalJSON(a, b string) bool {
+	var va, vb any
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func lookupJSON(data any, path []string) (string, bool) {
+	for _, key := range path {
+		switch v := data.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return "", false
+			}
+			data = next
+		case []any:
+			var idx int
+			if _, err := fmt.Sscanf(key, "%d", &idx); err != nil || idx < 0 || idx >= len(v) {
+				return "", false
+			}
+			data = v[idx]
+		default:
+			return "", false
+		}
+	}
+	switch v := data.(type) {
+	case string:
+		return v, true
+	case nil:
+		return "", true
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
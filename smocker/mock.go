@@ -2,23 +2,35 @@ package smocker
 
 import (
 	"encoding/json"
-	"net/http"
+	"fmt"
 	"time"
 )
 
 type Mock struct {
-	Request  MockRequest   `json:"request,omitempty" yaml:"request"`
-	Response *MockResponse `json:"response,omitempty" yaml:"response,omitempty"`
-	Context  *MockContext  `json:"context,omitempty" yaml:"context,omitempty"`
+	Request         MockRequest          `json:"request,omitempty" yaml:"request"`
+	Response        *MockResponse        `json:"response,omitempty" yaml:"response,omitempty"`
+	DynamicResponse *DynamicMockResponse `json:"dynamic_response,omitempty" yaml:"dynamic_response,omitempty"`
+	Proxy           *MockProxy           `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	Context         *MockContext         `json:"context,omitempty" yaml:"context,omitempty"`
+	State           *MockState           `json:"state,omitempty" yaml:"-"`
+	// Labels describe what the mock was built for. They are not sent to Smocker.
+	Labels map[string]string `json:"-" yaml:"-"`
 }
 
+// Validate checks that the mock has at most one kind of response.
 func (bm *Mock) Validate() error {
-	if bm.Response == nil {
-		return nil
+	n := 0
+	if bm.Response != nil {
+		n++
+	}
+	if bm.DynamicResponse != nil {
+		n++
 	}
-	// Clean up body in case of non Success Stats code
-	if bm.Response.Status >= http.StatusBadRequest {
-		bm.Response.Body = ""
+	if bm.Proxy != nil {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("mock must have at most one of response, dynamic response and proxy, got %d", n)
 	}
 	return nil
 }
@@ -45,6 +57,15 @@ func (bm BodyMatcher) MarshalJSON() ([]byte, error) {
 	return json.Marshal(bm.BodyJSON)
 }
 
+func (bm *BodyMatcher) UnmarshalJSON(data []byte) error {
+	var s StringMatcher
+	if err := json.Unmarshal(data, &s); err == nil && s.Matcher != "" {
+		bm.BodyString = &s
+		return nil
+	}
+	return json.Unmarshal(data, &bm.BodyJSON)
+}
+
 type MultiMapMatcher map[string]StringMatcherSlice
 
 type StringMatcherSlice []StringMatcher
@@ -68,10 +89,46 @@ type MockResponse struct {
 	Headers MapStringSlice `json:"headers,omitempty" yaml:"headers,omitempty"`
 }
 
+// Engine is the engine used to render a dynamic response.
+type Engine string
+
+// Engines supported by Smocker.
+const (
+	GoTemplateEngine     Engine = "go_template"
+	GoTemplateYAMLEngine Engine = "go_template_yaml"
+	GoTemplateJSONEngine Engine = "go_template_json"
+	LuaEngine            Engine = "lua"
+)
+
+// DynamicMockResponse is a response computed from the request by a script.
+// Go templates have access to the request as `.Request` with `Path`, `Method`,
+// `Body` (parsed if it is JSON), `QueryParams` and `Headers` fields.
+type DynamicMockResponse struct {
+	Engine Engine `json:"engine" yaml:"engine"`
+	Script string `json:"script" yaml:"script"`
+}
+
+// MockProxy forwards matched requests, with their path and query, to another host.
+type MockProxy struct {
+	Host           string         `json:"host" yaml:"host"`
+	FollowRedirect bool           `json:"follow_redirect,omitempty" yaml:"follow_redirect,omitempty"`
+	SkipVerifyTLS  bool           `json:"skip_verify_tls,omitempty" yaml:"skip_verify_tls,omitempty"`
+	KeepHost       bool           `json:"keep_host,omitempty" yaml:"keep_host,omitempty"`
+	Headers        MapStringSlice `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
 type MockContext struct {
 	Times uint `json:"times" yaml:"times"`
 }
 
+// MockState is filled by Smocker for registered mocks.
+type MockState struct {
+	ID           string    `json:"id"`
+	TimesCount   uint      `json:"times_count"`
+	Locked       bool      `json:"locked"`
+	CreationDate time.Time `json:"creation_date"`
+}
+
 func ShouldEqualJSON(value string) StringMatcher {
 	return StringMatcher{Matcher: "ShouldEqualJSON", Value: value}
 }
@@ -0,0 +1,15 @@
+package smocker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockValidate(t *testing.T) {
+	require.NoError(t, (&Mock{Response: &MockResponse{Status: 404, Body: "not found"}}).Validate())
+	require.NoError(t, (&Mock{DynamicResponse: &DynamicMockResponse{Engine: GoTemplateJSONEngine}}).Validate())
+	require.NoError(t, (&Mock{Proxy: &MockProxy{Host: "http://localhost"}}).Validate())
+	require.NoError(t, (&Mock{}).Validate())
+	require.Error(t, (&Mock{Response: &MockResponse{}, Proxy: &MockProxy{}}).Validate())
+}
@@ -0,0 +1,55 @@
+package smocker
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// forward sends the request to the proxy host and returns its response.
+func (p *MockProxy) forward(req *http.Request, body string) (*MockResponse, error) {
+	target := strings.TrimSuffix(p.Host, "/") + req.URL.Path
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+	out, err := http.NewRequestWithContext(req.Context(), req.Method, target, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build proxy request: %w", err)
+	}
+	out.Header = req.Header.Clone()
+	for name, values := range p.Headers {
+		out.Header.Del(name)
+		for _, v := range values {
+			out.Header.Add(name, v)
+		}
+	}
+	if p.KeepHost {
+		out.Host = req.Host
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: p.SkipVerifyTLS}, //nolint:gosec
+		},
+	}
+	if !p.FollowRedirect {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	res, err := client.Do(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call proxy host: %w", err)
+	}
+	response, err := BuildResponse(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy response: %w", err)
+	}
+	mr := &MockResponse{Status: response.StatusCode, Body: response.Body, Headers: make(MapStringSlice)}
+	for name, values := range response.Headers {
+		mr.Headers[name] = values
+	}
+	return mr, nil
+}
@@ -0,0 +1,478 @@
+package smocker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// StatusNoMockFound is the status code Smocker responds with when no mock matches a request.
+const StatusNoMockFound = 666
+
+// ServerVersion is the Smocker version whose API the in-process server implements.
+const ServerVersion = "0.18.5"
+
+// Server is an in-process replacement for a Smocker container. It serves
+// mocks on URL and exposes the Smocker admin API on AdminURL, so API and
+// MocksBuilder.Deploy can target it without Docker.
+type Server struct {
+	mu       sync.Mutex
+	sessions []*serverSession
+
+	mockServer  *httptest.Server
+	adminServer *httptest.Server
+}
+
+// NewServer starts a new in-process mock server. It has to be closed with Close.
+func NewServer() *Server {
+	s := &Server{}
+	s.mockServer = httptest.NewServer(http.HandlerFunc(s.serveMock))
+	s.adminServer = httptest.NewServer(s.adminHandler())
+	return s
+}
+
+// URL returns the base URL mocks are served on (Smocker's port 8080).
+func (s *Server) URL() string {
+	return s.mockServer.URL
+}
+
+// AdminURL returns the base URL of the admin API (Smocker's port 8081).
+func (s *Server) AdminURL() string {
+	return s.adminServer.URL
+}
+
+// API returns an API client for the server's admin API.
+func (s *Server) API() API {
+	return API{URL: s.AdminURL()}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.mockServer.Close()
+	s.adminServer.Close()
+}
+
+// Reset clears the sessions with their mocks and history of calls.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = nil
+}
+
+// ResetUnlocked clears the sessions with their history of calls, but keeps locked mocks.
+func (s *Server) ResetUnlocked() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var locked []*Mock
+	if len(s.sessions) > 0 {
+		locked = s.currentSession().lockedMocks()
+	}
+	s.sessions = nil
+	if len(locked) > 0 {
+		s.startSession("").mocks = locked
+	}
+}
+
+// LockMocks locks mocks with given IDs in the current session, so they survive
+// resets without force and are carried over to new sessions.
+func (s *Server) LockMocks(ids []string) []*Mock {
+	return s.setLocked(ids, true)
+}
+
+// UnlockMocks unlocks mocks with given IDs in the current session.
+func (s *Server) UnlockMocks(ids []string) []*Mock {
+	return s.setLocked(ids, false)
+}
+
+func (s *Server) setLocked(ids []string, locked bool) []*Mock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss := s.currentSession()
+	for _, m := range ss.mocks {
+		for _, id := range ids {
+			if m.State.ID == id {
+				m.State.Locked = locked
+			}
+		}
+	}
+	return ss.copyMocks()
+}
+
+// StartSession starts a new session, which becomes the current one.
+func (s *Server) StartSession(name string) Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.startSession(name).Session
+}
+
+// Sessions returns the sessions, oldest first.
+func (s *Server) Sessions() []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]Session, 0, len(s.sessions))
+	for _, ss := range s.sessions {
+		sessions = append(sessions, ss.Session)
+	}
+	return sessions
+}
+
+// AddMocks registers mocks in the current session. As in Smocker, the most
+// recently added mock takes precedence when several mocks match the same request.
+func (s *Server) AddMocks(mocks []*Mock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currentSession().addMocks(mocks)
+}
+
+// Mocks returns the mocks registered in the current session, most recently added first.
+func (s *Server) Mocks() []*Mock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentSession().copyMocks()
+}
+
+// History returns the calls received in the current session, oldest first.
+func (s *Server) History() History {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentSession().copyHistory()
+}
+
+// Verify verifies the mocks and the history of calls of the current session
+// the same way Smocker does.
+func (s *Server) Verify() *VerifyResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentSession().verify()
+}
+
+type serverSession struct {
+	Session
+	mocks   []*Mock
+	history History
+}
+
+func (s *Server) startSession(name string) *serverSession {
+	ss := &serverSession{Session: Session{ID: newID(), Name: name, Date: time.Now()}}
+	if len(s.sessions) > 0 {
+		ss.mocks = s.currentSession().lockedMocks()
+	}
+	s.sessions = append(s.sessions, ss)
+	return ss
+}
+
+func (s *Server) currentSession() *serverSession {
+	if len(s.sessions) == 0 {
+		return s.startSession("")
+	}
+	return s.sessions[len(s.sessions)-1]
+}
+
+// session returns the session with given ID or the current session if the ID is empty.
+func (s *Server) session(id string) (*serverSession, error) {
+	if id == "" {
+		return s.currentSession(), nil
+	}
+	for _, ss := range s.sessions {
+		if ss.ID == id {
+			return ss, nil
+		}
+	}
+	return nil, fmt.Errorf("session not found: %s", id)
+}
+
+func (ss *serverSession) addMocks(mocks []*Mock) {
+	for _, m := range mocks {
+		c := *m
+		c.State = &MockState{ID: newID(), CreationDate: time.Now()}
+		ss.mocks = append([]*Mock{&c}, ss.mocks...)
+	}
+}
+
+func (ss *serverSession) lockedMocks() []*Mock {
+	var mocks []*Mock
+	for _, m := range ss.mocks {
+		if m.State.Locked {
+			mocks = append(mocks, m)
+		}
+	}
+	return mocks
+}
+
+func (ss *serverSession) copyMocks() []*Mock {
+	mocks := make([]*Mock, 0, len(ss.mocks))
+	for _, m := range ss.mocks {
+		c := *m
+		state := *m.State
+		c.State = &state
+		mocks = append(mocks, &c)
+	}
+	return mocks
+}
+
+func (ss *serverSession) copyHistory() History {
+	history := make(History, 0, len(ss.history))
+	for _, e := range ss.history {
+		c := *e
+		history = append(history, &c)
+	}
+	return history
+}
+
+func (ss *serverSession) verify() *VerifyResult {
+	var res VerifyResult
+	for _, m := range ss.copyMocks() {
+		if m.Context != nil && m.Context.Times > 0 && m.State.TimesCount != m.Context.Times {
+			res.Mocks.Failures = append(res.Mocks.Failures, m)
+		}
+		if m.State.TimesCount == 0 {
+			res.Mocks.Unused = append(res.Mocks.Unused, m)
+		}
+	}
+	res.Mocks.Verified = len(res.Mocks.Failures) == 0
+	res.Mocks.AllUsed = len(res.Mocks.Unused) == 0
+	res.Mocks.Message = "All mocks match expectations"
+	if !res.Mocks.Verified {
+		res.Mocks.Message = "Some mocks don't match expectations"
+	}
+
+	res.History.Failures = ss.copyHistory().Filter(func(e *HistoryEntry) bool {
+		return e.Response.Status == StatusNoMockFound
+	})
+	res.History.Verified = len(res.History.Failures) == 0
+	res.History.Message = "History is clean"
+	if !res.History.Verified {
+		res.History.Message = "There are errors in the history"
+	}
+	return &res
+}
+
+func (ss *serverSession) match(req *http.Request, body string) *Mock {
+	for _, m := range ss.mocks {
+		if m.Context != nil && m.Context.Times > 0 && m.State.TimesCount >= m.Context.Times {
+			continue
+		}
+		if !m.Request.Match(req, body) {
+			continue
+		}
+		m.State.TimesCount++
+		return m
+	}
+	return nil
+}
+
+func (s *Server) serveMock(w http.ResponseWriter, req *http.Request) {
+	body, err := readBody(req)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	ss := s.currentSession()
+	m := ss.match(req, body)
+	entry := &HistoryEntry{
+		Request: HistoryRequest{
+			Path:        req.URL.Path,
+			Method:      req.Method,
+			Body:        HistoryBody(body),
+			QueryParams: req.URL.Query(),
+			Headers:     req.Header.Clone(),
+			Origin:      req.RemoteAddr,
+			Date:        time.Now(),
+		},
+	}
+	if m != nil {
+		entry.Context.MockID = m.State.ID
+		entry.Context.MockType = "static"
+		if m.DynamicResponse != nil {
+			entry.Context.MockType = "dynamic"
+		}
+		if m.Proxy != nil {
+			entry.Context.MockType = "proxy"
+		}
+	}
+	ss.history = append(ss.history, entry)
+	s.mu.Unlock()
+
+	rec := httptest.NewRecorder()
+	switch {
+	case m == nil:
+		writeJSON(rec, StatusNoMockFound, map[string]string{"message": "No mock found matching the request"})
+	case m.DynamicResponse != nil:
+		res, err := m.DynamicResponse.render(req, body)
+		if err != nil {
+			writeJSON(rec, http.StatusInternalServerError, map[string]string{"message": err.Error()})
+			break
+		}
+		writeMockResponse(rec, res)
+	case m.Proxy != nil:
+		res, err := m.Proxy.forward(req, body)
+		if err != nil {
+			writeJSON(rec, http.StatusBadGateway, map[string]string{"message": err.Error()})
+			break
+		}
+		writeMockResponse(rec, res)
+	case m.Response != nil:
+		writeMockResponse(rec, m.Response)
+	default:
+		writeJSON(rec, http.StatusInternalServerError, map[string]string{"message": "mock has no response"})
+	}
+
+	s.mu.Lock()
+	entry.Response = HistoryResponse{
+		Status:  rec.Code,
+		Body:    HistoryBody(rec.Body.String()),
+		Headers: rec.Header().Clone(),
+		Date:    time.Now(),
+	}
+	s.mu.Unlock()
+
+	for name, values := range rec.Header() {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+func writeMockResponse(w http.ResponseWriter, res *MockResponse) {
+	if d := res.Delay.duration(); d > 0 {
+		time.Sleep(d)
+	}
+	for name, values := range res.Headers {
+		for _, v := range values {
+			w.Header().Add(name, v)
+		}
+	}
+	status := res.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(res.Body))
+}
+
+func newID() string {
+	b := make([]byte, 8) //nolint:gomnd
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (d Delay) duration() time.Duration {
+	if d.Max <= d.Min {
+		return d.Min
+	}
+	return d.Min + time.Duration(mathrand.Int63n(int64(d.Max-d.Min))) //nolint:gosec
+}
+
+func (s *Server) adminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reset", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+			return
+		}
+		if req.URL.Query().Get("force") == "true" {
+			s.Reset()
+		} else {
+			s.ResetUnlocked()
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"message": "Reset successful"})
+	})
+	mux.HandleFunc("/mocks/lock", s.lockHandler(true))
+	mux.HandleFunc("/mocks/unlock", s.lockHandler(false))
+	mux.HandleFunc("/mocks", s.withSession(func(w http.ResponseWriter, req *http.Request, ss *serverSession) {
+		switch req.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, ss.copyMocks())
+		case http.MethodPost:
+			var mocks []*Mock
+			if err := json.NewDecoder(req.Body).Decode(&mocks); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("invalid mocks: %s", err)})
+				return
+			}
+			if req.URL.Query().Get("reset") == "true" {
+				ss.mocks = ss.lockedMocks()
+				ss.history = nil
+			}
+			ss.addMocks(mocks)
+			writeJSON(w, http.StatusOK, map[string]string{"message": "Mocks registered successfully"})
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+		}
+	}))
+	mux.HandleFunc("/history", s.withSession(func(w http.ResponseWriter, req *http.Request, ss *serverSession) {
+		if req.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, ss.copyHistory())
+	}))
+	mux.HandleFunc("/sessions", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.StartSession(req.URL.Query().Get("name")))
+	})
+	mux.HandleFunc("/sessions/summary", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Sessions())
+	})
+	mux.HandleFunc("/sessions/verify", s.withSession(func(w http.ResponseWriter, req *http.Request, ss *serverSession) {
+		if req.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, ss.verify())
+	}))
+	mux.HandleFunc("/version", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, VersionInfo{AppName: "smocker", BuildVersion: ServerVersion, BuildCommit: "in-process"})
+	})
+	return mux
+}
+
+func (s *Server) lockHandler(locked bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+			return
+		}
+		var ids []string
+		if err := json.NewDecoder(req.Body).Decode(&ids); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("invalid mock ids: %s", err)})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.setLocked(ids, locked))
+	}
+}
+
+// withSession resolves the session given in the `session` query param and
+// holds the server lock while the handler runs.
+func (s *Server) withSession(h func(http.ResponseWriter, *http.Request, *serverSession)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ss, err := s.session(req.URL.Query().Get("session"))
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": err.Error()})
+			return
+		}
+		h(w, req, ss)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,205 @@
+package smocker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url) //nolint:gosec
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestServerMatchesMocks(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	api := srv.API()
+
+	mocks := []*Mock{
+		NewMockBuilder().
+			SetRequestMethod(ShouldEqual("GET")).
+			SetRequestPath(ShouldEqual("/api/v3/ticker/price")).
+			AddRequestQueryParam("symbol", ShouldEqual("ETHBTC")).
+			SetResponseBody(`{"price":"1"}`).
+			Mock(),
+		NewMockBuilder().
+			SetRequestMethod(ShouldEqual("POST")).
+			SetRequestPath(ShouldEqual("/")).
+			SetRequestBodyString(ShouldContainSubstring("eth_chainId")).
+			SetResponseBody(`{"result":"1"}`).
+			Mock(),
+		NewMockBuilder().
+			SetRequestPath(ShouldMatch("^/v2/ticker/t.+$")).
+			AddRequestHeader("x-api-key", ShouldStartWith("abc")).
+			SetResponseStatus(http.StatusTeapot).
+			Mock(),
+	}
+	require.NoError(t, api.Reset(context.Background()))
+	require.NoError(t, api.AddMocks(context.Background(), mocks))
+
+	status, body := get(t, srv.URL()+"/api/v3/ticker/price?symbol=ETHBTC")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, `{"price":"1"}`, body)
+
+	status, _ = get(t, srv.URL()+"/api/v3/ticker/price?symbol=BTCUSD")
+	require.Equal(t, StatusNoMockFound, status)
+
+	resp, err := http.Post(srv.URL()+"/", "application/json", strings.NewReader(`{"method":"eth_chainId"}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL()+"/v2/ticker/tETHBTC", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Api-Key", "abcdef")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusTeapot, resp.StatusCode)
+}
+
+func TestServerMockPrecedenceAndTimes(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddMocks([]*Mock{
+		NewMockBuilder().SetRequestPath(ShouldEqual("/price")).SetResponseBody("second").Mock(),
+		NewMockBuilder().SetRequestPath(ShouldEqual("/price")).SetResponseBody("first").SetContextTimes(1).Mock(),
+	})
+
+	_, body := get(t, srv.URL()+"/price")
+	require.Equal(t, "first", body)
+	_, body = get(t, srv.URL()+"/price")
+	require.Equal(t, "second", body)
+
+	srv.Reset()
+	status, _ := get(t, srv.URL()+"/price")
+	require.Equal(t, StatusNoMockFound, status)
+}
+
+func TestBodyMatcherJSON(t *testing.T) {
+	bm := BodyMatcher{BodyJSON: map[string]StringMatcher{
+		"method":      ShouldEqual("eth_call"),
+		"params.0.to": ShouldEqual("0xabc"),
+		"id":          ShouldEqual("1"),
+	}}
+	require.True(t, bm.Match(`{"method":"eth_call","params":[{"to":"0xabc"},"latest"],"id":1}`))
+	require.False(t, bm.Match(`{"method":"eth_call","params":[{"to":"0xdef"},"latest"],"id":1}`))
+	require.False(t, bm.Match(`not json`))
+}
+
+func TestServerHistory(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	api := srv.API()
+
+	mock := NewMockBuilder().
+		SetRequestPath(ShouldEqual("/api/v3/ticker/price")).
+		AddRequestQueryParam("symbol", ShouldEqual("ETHBTC")).
+		SetResponseBody(`{"price":"1"}`).
+		Mock()
+	require.NoError(t, api.AddMocks(context.Background(), []*Mock{mock}))
+
+	get(t, srv.URL()+"/api/v3/ticker/price?symbol=ETHBTC")
+	get(t, srv.URL()+"/api/v3/ticker/price?symbol=BTCUSD")
+
+	history, err := api.History(context.Background())
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	matched := history.FilterByPath("/api/v3/ticker/price?symbol=ETHBTC")
+	require.Len(t, matched, 1)
+	require.Equal(t, "GET", matched[0].Request.Method)
+	require.Equal(t, http.StatusOK, matched[0].Response.Status)
+	require.Equal(t, HistoryBody(`{"price":"1"}`), matched[0].Response.Body)
+	require.NotNil(t, matched[0].Mock)
+	require.Equal(t, mock.Request.Path, matched[0].Mock.Request.Path)
+
+	unmatched := history.FilterByPath("/api/v3/ticker/price?symbol=BTCUSD")
+	require.Len(t, unmatched, 1)
+	require.Nil(t, unmatched[0].Mock)
+	require.Equal(t, StatusNoMockFound, unmatched[0].Response.Status)
+
+	require.NoError(t, api.Reset(context.Background()))
+	history, err = api.History(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
+
+func TestServerSessions(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	api := srv.API()
+	ctx := context.Background()
+
+	mock := NewMockBuilder().SetRequestPath(ShouldEqual("/price")).SetResponseBody("1").Mock()
+
+	first, err := api.StartSession(ctx, "first")
+	require.NoError(t, err)
+	require.NoError(t, api.AddSessionMocks(ctx, first.ID, []*Mock{mock}))
+	get(t, srv.URL()+"/price")
+
+	second, err := api.StartSession(ctx, "second")
+	require.NoError(t, err)
+	status, _ := get(t, srv.URL()+"/price")
+	require.Equal(t, StatusNoMockFound, status)
+
+	sessions, err := api.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, "first", sessions[0].Name)
+	require.Equal(t, "second", sessions[1].Name)
+
+	summary, err := api.SessionSummary(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, "first", summary.Name)
+	require.Len(t, summary.Mocks, 1)
+	require.Len(t, summary.History, 1)
+	require.Equal(t, http.StatusOK, summary.History[0].Response.Status)
+
+	result, err := api.Verify(ctx, second.ID)
+	require.NoError(t, err)
+	require.False(t, result.History.Verified)
+
+	_, err = api.SessionSummary(ctx, "unknown")
+	require.Error(t, err)
+}
+
+func TestServerDynamicResponse(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddMocks([]*Mock{
+		NewMockBuilder().
+			SetRequestPath(ShouldEqual("/json")).
+			SetDynamicResponse(GoTemplateJSONEngine,
+				`{"status": 201, "body": {"echo": {{ .Request.Body.value | toJson }}}}`).
+			Mock(),
+		NewMockBuilder().
+			SetRequestPath(ShouldEqual("/yaml")).
+			SetDynamicResponse(GoTemplateYAMLEngine, "status: 200\nbody: '{{ index .Request.QueryParams.name 0 }}'").
+			Mock(),
+	})
+
+	resp, err := http.Post(srv.URL()+"/json", "application/json", strings.NewReader(`{"value":[1,2]}`))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.JSONEq(t, `{"echo":[1,2]}`, string(body))
+
+	status, res := get(t, srv.URL()+"/yaml?name=infestor")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "infestor", res)
+}
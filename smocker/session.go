@@ -0,0 +1,90 @@
+package smocker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Session groups mocks and the history of calls. Mocks and history are always
+// bound to the current (latest) session unless a session ID is given.
+type Session struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name"`
+	Date time.Time `json:"date"`
+}
+
+// SessionSummary is a session with its mocks and history of calls.
+type SessionSummary struct {
+	Session
+	Mocks   []*Mock
+	History History
+}
+
+// StartSession Start a new session, which becomes the current one.
+func (a *API) StartSession(ctx context.Context, name string) (*Session, error) {
+	request := Request{
+		Method:      Post,
+		BaseURL:     fmt.Sprintf("%s/sessions", a.URL),
+		QueryParams: map[string]string{"name": name},
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to start session: %s", res.Body)
+	}
+	var session Session
+	if err := json.Unmarshal([]byte(res.Body), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// ListSessions Get the list of sessions, oldest first.
+func (a *API) ListSessions(ctx context.Context) ([]*Session, error) {
+	request := Request{
+		Method:  Get,
+		BaseURL: fmt.Sprintf("%s/sessions/summary", a.URL),
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list sessions: %s", res.Body)
+	}
+	var sessions []*Session
+	if err := json.Unmarshal([]byte(res.Body), &sessions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// SessionSummary Get the session with given ID together with its mocks and history of calls.
+func (a *API) SessionSummary(ctx context.Context, sessionID string) (*SessionSummary, error) {
+	sessions, err := a.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if s.ID != sessionID {
+			continue
+		}
+		summary := &SessionSummary{Session: *s}
+		if summary.Mocks, err = a.SessionMocks(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		if summary.History, err = a.SessionHistory(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		return summary, nil
+	}
+	return nil, fmt.Errorf("session not found: %s", sessionID)
+}
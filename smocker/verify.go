@@ -0,0 +1,61 @@
+package smocker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// VerifyResult is the result of a session verification.
+type VerifyResult struct {
+	Mocks   VerifyMocksResult   `json:"mocks"`
+	History VerifyHistoryResult `json:"history"`
+}
+
+type VerifyMocksResult struct {
+	// Verified is false if some mocks were not called the expected number of times.
+	Verified bool    `json:"verified"`
+	AllUsed  bool    `json:"all_used"`
+	Message  string  `json:"message"`
+	Failures []*Mock `json:"failures,omitempty"`
+	Unused   []*Mock `json:"unused,omitempty"`
+}
+
+type VerifyHistoryResult struct {
+	// Verified is false if some requests did not match any mock.
+	Verified bool    `json:"verified"`
+	Message  string  `json:"message"`
+	Failures History `json:"failures,omitempty"`
+}
+
+// OK reports whether all mocks were used as expected and every request matched a mock.
+func (r *VerifyResult) OK() bool {
+	return r.Mocks.Verified && r.Mocks.AllUsed && r.History.Verified
+}
+
+// Verify Verify the session with given ID, or the current session if the ID is empty.
+func (a *API) Verify(ctx context.Context, sessionID string) (*VerifyResult, error) {
+	request := Request{
+		Method:  Post,
+		BaseURL: fmt.Sprintf("%s/sessions/verify", a.URL),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+	if sessionID != "" {
+		request.QueryParams = map[string]string{"session": sessionID}
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to verify session: %s", res.Body)
+	}
+	var result VerifyResult
+	if err := json.Unmarshal([]byte(res.Body), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal verify result: %w", err)
+	}
+	return &result, nil
+}
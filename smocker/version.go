@@ -0,0 +1,122 @@
+package smocker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinVersion is the oldest Smocker version that supports everything infestor
+// sends: sessions, dynamic and proxy responses, and locked mocks.
+const MinVersion = "0.18.0"
+
+// ErrIncompatibleVersion is returned when the Smocker server is older than MinVersion.
+var ErrIncompatibleVersion = errors.New("incompatible smocker version")
+
+// ErrUnknownVersion is returned when the Smocker server version is not semver,
+// e.g. of a development build, so its compatibility can't be checked. Callers
+// may ignore it to use such servers.
+var ErrUnknownVersion = errors.New("unknown smocker version")
+
+const (
+	waitReadyMinBackoff = 50 * time.Millisecond
+	waitReadyMaxBackoff = time.Second
+)
+
+// VersionInfo is the build information reported by Smocker.
+type VersionInfo struct {
+	AppName      string `json:"app_name"`
+	BuildVersion string `json:"build_version"`
+	BuildCommit  string `json:"build_commit"`
+	BuildDate    string `json:"build_date"`
+}
+
+// Version Get the version of Smocker. Fails with ErrIncompatibleVersion if the
+// server is older than MinVersion, and with ErrUnknownVersion if its version is
+// not semver. The version info is returned with both errors.
+func (a *API) Version(ctx context.Context) (*VersionInfo, error) {
+	request := Request{
+		Method:  Get,
+		BaseURL: fmt.Sprintf("%s/version", a.URL),
+	}
+	res, err := SendWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get version: %s", res.Body)
+	}
+	var info VersionInfo
+	if err := json.Unmarshal([]byte(res.Body), &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal version: %w", err)
+	}
+	if err := checkVersion(info.BuildVersion); err != nil {
+		return &info, err
+	}
+	return &info, nil
+}
+
+// WaitReady Wait until Smocker responds to the version request, retrying with
+// an increasing backoff until the context is done. Fails immediately if the
+// server version is incompatible or unknown, see Version.
+func (a *API) WaitReady(ctx context.Context) error {
+	backoff := waitReadyMinBackoff
+	for {
+		_, err := a.Version(ctx)
+		if err == nil || errors.Is(err, ErrIncompatibleVersion) || errors.Is(err, ErrUnknownVersion) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("smocker at %s is not ready: %w", a.URL, err)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > waitReadyMaxBackoff {
+			backoff = waitReadyMaxBackoff
+		}
+	}
+}
+
+func checkVersion(version string) error {
+	v, err := parseVersion(version)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownVersion, err)
+	}
+	minVersion, _ := parseVersion(MinVersion)
+	for i := range v {
+		if v[i] != minVersion[i] {
+			if v[i] < minVersion[i] {
+				return fmt.Errorf("%w: %s, at least %s is required", ErrIncompatibleVersion, version, MinVersion)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseVersion parses `major.minor.patch` versions, with optional `v` prefix
+// and pre-release suffix.
+func parseVersion(version string) ([3]int, error) {
+	var v [3]int
+	s := strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != len(v) {
+		return v, fmt.Errorf("invalid version %q", version)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q", version)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
@@ -0,0 +1,85 @@
+package smocker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		err     error
+	}{
+		{MinVersion, nil},
+		{"0.18.5", nil},
+		{"v0.19.0", nil},
+		{"1.0.0-rc1", nil},
+		{"0.17.9", ErrIncompatibleVersion},
+		{"0.9.0", ErrIncompatibleVersion},
+		{"dev", ErrUnknownVersion},
+		{"3f2c1a9", ErrUnknownVersion},
+		{"", ErrUnknownVersion},
+	}
+	for _, tt := range tests {
+		err := checkVersion(tt.version)
+		if tt.err == nil {
+			require.NoError(t, err, tt.version)
+		} else {
+			require.ErrorIs(t, err, tt.err, tt.version)
+		}
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	api := srv.API()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, api.WaitReady(ctx))
+
+	info, err := api.Version(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ServerVersion, info.BuildVersion)
+}
+
+func TestWaitReadyIncompatible(t *testing.T) {
+	old := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"app_name":"smocker","build_version":"0.12.0"}`))
+	}))
+	defer old.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := NewAPI(old.URL).WaitReady(ctx)
+	require.ErrorIs(t, err, ErrIncompatibleVersion)
+	require.NoError(t, ctx.Err())
+}
+
+func TestWaitReadyDevBuild(t *testing.T) {
+	dev := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"app_name":"smocker","build_version":"dev"}`))
+	}))
+	defer dev.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := NewAPI(dev.URL).WaitReady(ctx)
+	require.ErrorIs(t, err, ErrUnknownVersion)
+	require.NoError(t, ctx.Err())
+}
+
+func TestWaitReadyTimeout(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	require.Error(t, NewAPI(down.URL).WaitReady(ctx))
+}
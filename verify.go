@@ -0,0 +1,115 @@
+package infestor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chronicleprotocol/infestor/origin"
+	"github.com/chronicleprotocol/infestor/smocker"
+)
+
+// VerifyReport lists mocks that were never hit and requests that matched no mock.
+// Unused auxiliary mocks, see origin.LabelAuxiliary, are listed separately and
+// don't fail the verification.
+type VerifyReport struct {
+	Unused          []UnusedMock
+	UnusedAuxiliary []UnusedMock
+	Unmatched       smocker.History
+}
+
+// UnusedMock is a mock that was never hit, together with the exchange mocks it was built from.
+// Origins is empty if the mock was not added through the MocksBuilder.
+type UnusedMock struct {
+	Origins []*origin.ExchangeMock
+	Mock    *smocker.Mock
+}
+
+// OK reports whether every mock was used and every request matched a mock.
+func (r *VerifyReport) OK() bool {
+	return len(r.Unused) == 0 && len(r.Unmatched) == 0
+}
+
+func (r *VerifyReport) String() string {
+	var sb strings.Builder
+	for _, u := range r.Unused {
+		if len(u.Origins) > 0 {
+			symbols := make([]string, 0, len(u.Origins))
+			for _, o := range u.Origins {
+				symbols = append(symbols, o.Symbol.String())
+			}
+			fmt.Fprintf(&sb, "unused mock: %s %s (%s %s)\n",
+				u.Origins[0].Name, strings.Join(symbols, ","), u.Mock.Request.Method.Value, u.Mock.Request.Path.Value)
+			continue
+		}
+		fmt.Fprintf(&sb, "unused mock: %s %s\n", u.Mock.Request.Method.Value, u.Mock.Request.Path.Value)
+	}
+	for _, e := range r.Unmatched {
+		fmt.Fprintf(&sb, "unmatched request: %s %s\n", e.Request.Method, e.Request.URL())
+	}
+	return sb.String()
+}
+
+// Verify verifies the smocker session started by Deploy (or the current one) and maps unused mocks
+// back to the exchange mocks added to the builder. It should be called after the tested client
+// made its requests.
+func (mb *MocksBuilder) Verify(ctx context.Context, api smocker.API) (*VerifyReport, error) {
+	result, err := api.Verify(ctx, mb.sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify session: %w", err)
+	}
+
+	// Labels are not sent to Smocker, so they are found by matching unused mocks with built ones.
+	built, err := mb.Build()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]map[string]string, len(built))
+	for _, m := range built {
+		key, err := mockKey(m)
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = m.Labels
+	}
+
+	report := &VerifyReport{Unmatched: result.History.Failures}
+	for _, m := range result.Mocks.Unused {
+		key, err := mockKey(m)
+		if err != nil {
+			return nil, err
+		}
+		u := UnusedMock{Origins: mb.findOrigins(labels[key]), Mock: m}
+		if labels[key][origin.LabelAuxiliary] == "true" {
+			report.UnusedAuxiliary = append(report.UnusedAuxiliary, u)
+			continue
+		}
+		report.Unused = append(report.Unused, u)
+	}
+	return report, nil
+}
+
+// findOrigins returns the exchange mocks a mock with given labels was built for.
+// Mocks without symbols are attributed to all exchange mocks of the origin.
+func (mb *MocksBuilder) findOrigins(labels map[string]string) []*origin.ExchangeMock {
+	name, ok := labels[origin.LabelOrigin]
+	if !ok {
+		return nil
+	}
+	var symbols map[string]bool
+	if s, ok := labels[origin.LabelSymbols]; ok {
+		symbols = make(map[string]bool)
+		for _, symbol := range strings.Split(s, ",") {
+			symbols[symbol] = true
+		}
+	}
+	var res []*origin.ExchangeMock
+	for i := range mb.mocks[name] {
+		e := &mb.mocks[name][i]
+		if symbols != nil && !symbols[e.Symbol.String()] {
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}